@@ -2,22 +2,10 @@
 
 set -e
 
-# データベースの接続情報 (docker-compose.ymlと一致させる)
-DB_USER="root"
-DB_PASS="Wario-51"
-DB_HOST="db"
-DB_PORT=3306
-DB_NAME="SeiCheese"
-
-# gooseの設定 (コンテナ内のパス)
-MIGRATIONS_DIR="/home/user/go/src/app/database/migrations"
-
-
 echo "Running migrations..."
 
-# gooseを実行
-docker compose exec go bash -c "goose -dir ${MIGRATIONS_DIR} mysql \"${DB_USER}:${DB_PASS}@tcp(${DB_HOST}:${DB_PORT})/${DB_NAME}?parseTime=true\" up"
-
+# アプリケーションに埋め込まれたマイグレーションを適用する (接続情報はコンテナの.envから読み込む)
+docker compose exec go go run ./cmd/app migrate up
 
 echo "Migrations complete. Generating code..."
 
@@ -0,0 +1,16 @@
+#!/bin/bash
+
+set -e
+
+# docker-compose.ymlのdbコンテナ（ホスト側の3312番ポート）に対してデータベースのテストを実行する
+# テストごとに使い捨てのデータベースを作成・削除するため、SeiCheeseのデータには影響しない
+# TEST_DB_DSNが設定済みの場合はその接続先を使う
+DB_USER="root"
+DB_PASS="Wario-51"
+DB_HOST="127.0.0.1"
+DB_PORT=3312
+
+export TEST_DB_DSN="${TEST_DB_DSN:-${DB_USER}:${DB_PASS}@tcp(${DB_HOST}:${DB_PORT})/}"
+
+cd ${PWD}/src
+go test ./internal/repository/... "$@"
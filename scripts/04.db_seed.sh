@@ -0,0 +1,11 @@
+#!/bin/bash
+
+set -e
+
+echo "Seeding database..."
+
+# 埋め込まれた初期データ（src/database/seeds）を投入する (何度実行しても同じ結果になる)
+# 任意のフィクスチャを投入する場合は引数にファイルまたはディレクトリを指定する
+docker compose exec go go run ./cmd/app seed "$@"
+
+echo "Seeding complete."
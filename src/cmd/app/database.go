@@ -0,0 +1,28 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"os"
+
+	"seicheese/internal/config"
+	"seicheese/internal/infrastructure/database"
+)
+
+// openDatabase はサブコマンド用にデータベースへ接続する
+// 設定はサーバーと同じ（CONFIG_FILEと環境変数）だが、検証するのはデータベースの設定のみ
+func openDatabase() *sql.DB {
+	cfg, err := config.Load(os.Getenv(config.EnvFile))
+	if err != nil {
+		log.Fatalf("Config error: %v", err)
+	}
+	if err := cfg.Database.Validate(); err != nil {
+		log.Fatalf("Config error: %v", err)
+	}
+
+	db, err := database.InitializeDB(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Database initialization error: %v", err)
+	}
+	return db
+}
@@ -2,76 +2,280 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"seicheese/internal/apperror"
+	"seicheese/internal/appversion"
+	"seicheese/internal/auth"
+	"seicheese/internal/buildinfo"
+	"seicheese/internal/config"
 	"seicheese/internal/handler"
+	"seicheese/internal/i18n"
 	firebase "seicheese/internal/infrastructure"
 	"seicheese/internal/infrastructure/database"
+	"seicheese/internal/infrastructure/storage"
+	"seicheese/internal/logging"
+	"seicheese/internal/metrics"
+	"seicheese/internal/middleware"
 	router "seicheese/internal/middleware/router"
+	"seicheese/internal/ratelimit"
+	"seicheese/internal/repository"
+	"seicheese/internal/security"
+	"seicheese/internal/tracing"
+	"seicheese/internal/utils"
+	"seicheese/internal/validation"
+	"seicheese/services"
+	"syscall"
 
+	fb "firebase.google.com/go/v4"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
 )
 
 func main() {
+	// サブコマンド
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			runMigrate(os.Args[2:])
+			return
+		case "seed":
+			runSeed(os.Args[2:])
+			return
+		}
+	}
+
+	configFile := flag.String("config", os.Getenv(config.EnvFile), "設定ファイル（YAML）のパス")
+	autoMigrate := flag.Bool("auto-migrate", false, "起動時に未適用のマイグレーションを適用する（AUTO_MIGRATE=trueと同じ）")
+	flag.Parse()
+
+	// 設定の読み込みと検証（不足している設定があれば起動しない）
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatalf("Config error: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Config error:\n%v", err)
+	}
+	if *autoMigrate {
+		cfg.AutoMigrate = true
+	}
+
+	// JSON形式の構造化ログ（標準のlogパッケージの出力もこのロガーを通る）
+	logLevel, _ := logging.ParseLevel(cfg.LogLevel)
+	logger := logging.New(os.Stdout, logLevel)
+	slog.SetDefault(logger)
+
+	// トレースの送り先の設定（TRACING_EXPORTER=noneの場合はスパンを記録しない）
+	info := buildinfo.Get()
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing, info)
+	if err != nil {
+		fatal("tracing initialization error", err)
+	}
+
+	if cfg.GoogleMapsAPIKey == "" {
+		slog.Warn("GOOGLE_MAPS_API_KEY is not set; address lookup will fail")
+	}
 
 	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	// クライアントのIPアドレス（アクセスログとレート制限に使う）は信頼するプロキシが付けたX-Forwarded-Forからだけ読む
+	e.IPExtractor = security.IPExtractor(&cfg.Security)
 
-	// CORS設定
-	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"},
-		AllowMethods: []string{http.MethodGet, http.MethodPost},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
-	}))
+	// リクエストIDの採番とアクセスログ（他のミドルウェアのログにもリクエストIDを付与するため最初に適用する）
+	// アクセスログは処理後のcontextで出力するため、後で適用するtracing.Middlewareのtrace_id・span_idが付く
+	e.Use(logging.Middleware(logger))
 
-	// Firebaseの初期化
-	firebaseApp, err := firebase.InitializeFirebaseApp()
+	// ルート・ステータスごとのリクエスト数と処理時間
+	e.Use(metrics.Middleware())
+
+	// リクエストごとのスパン（SQL・トークン検証・Geocoding APIのスパンはこの子になる）
+	e.Use(tracing.Middleware())
+
+	// エラーレスポンスの形式を統一
+	e.HTTPErrorHandler = apperror.HTTPErrorHandler
+
+	// Accept-Languageからレスポンスの言語を決める
+	e.Use(i18n.Middleware())
+
+	// CORS設定（許可するオリジン・メソッドは環境ごとに設定する）
+	e.Use(security.CORS(&cfg.CORS))
+
+	// セキュリティヘッダーとリクエストボディの上限
+	e.Use(security.Headers(&cfg.Security))
+	e.Use(security.BodyLimit(&cfg.Security))
+
+	// アプリのバージョンの確認（最低サポートバージョンより古い場合はUPGRADE_REQUIRED）
+	versions, err := appversion.NewPolicy(&cfg.AppVersion)
 	if err != nil {
-		log.Fatalf("Firebase initialization error: %v", err)
+		fatal("app version policy error", err)
 	}
+	e.Use(middleware.AppVersionMiddleware(versions))
 
-	authClient, err := firebaseApp.Auth(context.Background())
+	// Firebaseの初期化（ローカル認証かつローカルストレージの場合は不要）
+	var firebaseApp *fb.App
+	if cfg.UsesFirebase() {
+		app, err := firebase.InitializeFirebaseApp(cfg.FirebaseSDKPath, cfg.Auth.ProjectID, cfg.Auth.UsesEmulator())
+		if err != nil {
+			fatal("firebase initialization error", err)
+		}
+		firebaseApp = app
+	}
+	if cfg.Auth.UsesEmulator() {
+		slog.Warn("using Firebase Auth Emulator; ID token signatures are not verified", "host", cfg.Auth.EmulatorHost)
+	}
+
+	// 認証基盤の初期化
+	authProvider, err := auth.InitializeProvider(context.Background(), firebaseApp, &cfg.Auth)
 	if err != nil {
-		log.Fatalf("Auth client initialization error: %v", err)
+		fatal("auth provider initialization error", err)
+	}
+	authProvider = auth.Trace(authProvider)
+	// 同じトークンの検証を省く（キャッシュのヒット時は検証のスパンを記録しない）
+	if cfg.Auth.TokenCacheSize > 0 {
+		authProvider = auth.NewCachingProvider(authProvider, cfg.Auth.TokenCacheSize, cfg.Auth.RevocationCheckInterval)
 	}
 
 	// データベース接続
-	dbConfig := database.NewDBConfig()
-	db, err := database.InitializeDB(dbConfig)
+	db, err := database.InitializeDB(&cfg.Database)
 	if err != nil {
-		log.Fatalf("Database initialization error: %v", err)
+		fatal("database initialization error", err)
 	}
 	defer db.Close()
 
+	// マイグレーションの自動適用
+	if cfg.AutoMigrate {
+		if err := database.Migrate(context.Background(), db, "up", os.Stdout); err != nil {
+			fatal("migration error", err)
+		}
+	}
+
+	// リポジトリの初期化
+	repos := repository.NewSQLRepositories(db)
+
+	// リクエストの検証（外部キーの存在確認にリポジトリを使う）
+	e.Validator = validation.New(repos.ExistsCheckers())
+
+	// ストレージの初期化（アバター画像など）
+	blobStore, err := storage.InitializeBlobStore(context.Background(), firebaseApp, &cfg.Storage)
+	if err != nil {
+		fatal("storage initialization error", err)
+	}
+	if localStore, ok := blobStore.(*storage.LocalBlobStore); ok {
+		e.Static("/uploads", localStore.Dir())
+	}
+
+	// NGワードの読み込み（デフォルトのNGワードにNG_WORDS_FILEの語を加える）
+	ngWords, err := utils.LoadNGWords(cfg.NGWordsFile)
+	if err != nil {
+		fatal("ng words initialization error", err)
+	}
+
+	// サービスの初期化
+	userService := &services.UserService{
+		Users:   repos.Users,
+		NGWords: ngWords,
+	}
+
+	geocoder := &services.GeocodingService{
+		APIKey: cfg.GoogleMapsAPIKey,
+	}
+
 	// ハンドラーの初期化
 	authHandler := &handler.AuthHandler{
-		DB:         db,
-		AuthClient: authClient,
+		Users:       repos.Users,
+		UserService: userService,
+		Versions:    versions,
 	}
 
 	genreHandler := &handler.GenreHandler{
-		DB: db,
+		Genres: repos.Genres,
 	}
 
 	seichiHandler := &handler.SeichiHandler{
-		DB: db,
+		Seichies: repos.Seichies,
+		Geocoder: geocoder,
 	}
 
 	contentHandler := &handler.ContentHandler{
-		DB: db,
+		Contents: repos.Contents,
+	}
+
+	placeHandler := &handler.PlaceHandler{
+		Places:   repos.Places,
+		Geocoder: geocoder,
+	}
+
+	checkinHandler := &handler.CheckinHandler{
+		Checkins: repos.Checkins,
+	}
+
+	userHandler := &handler.UserHandler{
+		Users:       repos.Users,
+		UserService: userService,
+		Genres:      repos.Genres,
+		Seichies:    repos.Seichies,
+		Checkins:    repos.Checkins,
+		Storage:     blobStore,
+		AuthUsers:   authProvider,
+	}
+
+	healthHandler := &handler.HealthHandler{
+		Checks: []handler.ReadinessCheck{
+			{Name: "database", Check: db.PingContext},
+			{Name: "firebase", Check: cfg.CheckFirebase},
+			{Name: "geocoder", Check: geocoder.CheckConfig},
+		},
+		BuildInfo: info,
 	}
 
+	// 登録系のルートのリクエスト数の制限（無効の場合はnil）
+	limiter := ratelimit.NewLimiter(&cfg.RateLimit, ratelimit.NewMemoryStore())
+
 	// ルーターの登録
-	router.RegisterAuthRoutes(e, authClient, authHandler)
+	router.RegisterHealthRoutes(e, healthHandler)
+	router.RegisterMetricsRoutes(e, metrics.Handler(metrics.NewRegistry(db)))
+	router.RegisterAuthRoutes(e, authProvider, &cfg.Auth, authHandler, limiter)
 	router.RegisterGenreRoutes(e, genreHandler)
-	router.RegisterSeichiRoutes(e, seichiHandler, authClient)
-	router.RegisterContentRoutes(e, contentHandler, authClient)
+	router.RegisterSeichiRoutes(e, seichiHandler, authProvider, &cfg.Auth, repos.Users, limiter)
+	router.RegisterContentRoutes(e, contentHandler, authProvider, &cfg.Auth, limiter)
+	router.RegisterPlaceRoutes(e, placeHandler, authProvider, &cfg.Auth, limiter)
+	router.RegisterCheckinRoutes(e, checkinHandler, authProvider, &cfg.Auth, repos.Users, limiter)
+	router.RegisterUserRoutes(e, userHandler, authProvider, &cfg.Auth, repos.Users, limiter)
+
+	// サーバー起動（SIGINT・SIGTERMを受け取るまで）
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		slog.Info("server started", "port", cfg.Port, "version", healthHandler.BuildInfo.Version)
+		if err := e.Start(":" + cfg.Port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("server error", err)
+		}
+	}()
+	<-ctx.Done()
 
-	// サーバー起動
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "1300"
+	// 新しいリクエストの受付を止め、処理中のリクエストの完了を待ってから停止する
+	slog.Info("shutting down", "timeout", cfg.ShutdownTimeout.String())
+	healthHandler.MarkShuttingDown()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		slog.Error("shutdown error", "error", err)
 	}
-	e.Logger.Fatal(e.Start(":" + port))
+	// 送信待ちのスパンを送り出す
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("tracing shutdown error", "error", err)
+	}
+}
+
+// fatal はエラーを記録して異常終了する
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"seicheese/internal/infrastructure/database"
+)
+
+// runMigrate はmigrateサブコマンドを実行する（接続先はサーバーと同じデータベース）
+//
+//	seicheese migrate up|down|status|redo
+func runMigrate(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "usage: %s migrate %s\n", os.Args[0], strings.Join(database.MigrateCommands, "|"))
+		os.Exit(2)
+	}
+
+	db := openDatabase()
+	defer db.Close()
+
+	if err := database.Migrate(context.Background(), db, args[0], os.Stdout); err != nil {
+		log.Fatalf("Migration error: %v", err)
+	}
+}
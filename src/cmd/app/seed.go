@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	schema "seicheese/database"
+	"seicheese/internal/seed"
+)
+
+// runSeed はseedサブコマンドを実行する（接続先はサーバーと同じデータベース）
+// ファイルまたはディレクトリを指定しない場合は埋め込まれた初期データ（database/seeds）を投入する
+//
+//	seicheese seed [file|dir ...]
+func runSeed(args []string) {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: %s seed [file.yaml|file.json|dir ...]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	var fixture *seed.Fixture
+	var err error
+	if flags.NArg() == 0 {
+		fixture, err = seed.LoadFS(schema.Seeds())
+	} else {
+		fixture, err = seed.Load(flags.Args()...)
+	}
+	if err != nil {
+		log.Fatalf("Fixture load error: %v", err)
+	}
+
+	db := openDatabase()
+	defer db.Close()
+
+	result, err := seed.Apply(context.Background(), db, fixture)
+	if err != nil {
+		log.Fatalf("Seed error: %v", err)
+	}
+	result.WriteTo(os.Stdout)
+}
@@ -0,0 +1,53 @@
+// ローカル認証（AUTH_MODE=local）用のIDトークンを発行する開発用コマンド
+//
+//	go run ./cmd/devtoken -uid test-user -claims '{"role":"admin"}'
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"seicheese/internal/auth"
+	"seicheese/internal/config"
+)
+
+func main() {
+	uid := flag.String("uid", "", "トークンに含めるFirebase UID（必須）")
+	rawClaims := flag.String("claims", "", "追加するクレーム（JSONオブジェクト）")
+	ttl := flag.Duration("ttl", time.Hour, "トークンの有効期間")
+	flag.Parse()
+
+	if *uid == "" {
+		log.Fatal("-uid is required")
+	}
+
+	claims := map[string]interface{}{}
+	if *rawClaims != "" {
+		if err := json.Unmarshal([]byte(*rawClaims), &claims); err != nil {
+			log.Fatalf("invalid -claims: %v", err)
+		}
+	}
+	if _, ok := claims["exp"]; !ok {
+		claims["exp"] = time.Now().Add(*ttl).Unix()
+	}
+
+	// 署名鍵などはサーバーと同じ設定（CONFIG_FILEと環境変数LOCAL_AUTH_*）から読み込む
+	cfg, err := config.Load(os.Getenv(config.EnvFile))
+	if err != nil {
+		log.Fatalf("Config error: %v", err)
+	}
+	issuer, err := auth.NewLocalIssuerFromConfig(&cfg.Auth)
+	if err != nil {
+		log.Fatalf("Local issuer initialization error: %v", err)
+	}
+
+	token, err := issuer.Mint(*uid, claims)
+	if err != nil {
+		log.Fatalf("Failed to mint token: %v", err)
+	}
+	fmt.Println(token)
+}
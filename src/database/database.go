@@ -0,0 +1,29 @@
+// Package database はgoose形式のマイグレーション（migrations/*.sql）と初期データ（seeds/*）をバイナリに埋め込む
+package database
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations/*.sql seeds/*.yaml
+var embedded embed.FS
+
+// Migrations は埋め込まれたマイグレーションを返す（ファイル名はmigrationsディレクトリからの相対パス）
+func Migrations() fs.FS {
+	return sub("migrations")
+}
+
+// Seeds は埋め込まれた初期データを返す（ファイル名はseedsディレクトリからの相対パス）
+func Seeds() fs.FS {
+	return sub("seeds")
+}
+
+func sub(dir string) fs.FS {
+	fsys, err := fs.Sub(embedded, dir)
+	if err != nil {
+		// 埋め込み時に存在が保証されているパスのため発生しない
+		panic(err)
+	}
+	return fsys
+}
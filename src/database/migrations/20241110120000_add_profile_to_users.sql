@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN display_name VARCHAR(50) AFTER firebase_id,
+    ADD COLUMN avatar_url VARCHAR(512) AFTER display_name,
+    ADD COLUMN bio VARCHAR(500) AFTER avatar_url,
+    ADD COLUMN favorite_genre_ids JSON AFTER bio;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN favorite_genre_ids,
+    DROP COLUMN bio,
+    DROP COLUMN avatar_url,
+    DROP COLUMN display_name;
+-- +goose StatementEnd
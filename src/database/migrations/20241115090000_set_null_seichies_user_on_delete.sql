@@ -0,0 +1,32 @@
+-- +goose Up
+-- 退会したユーザーの聖地は削除せず、登録者を匿名化（NULL）して残す
+-- +goose StatementBegin
+ALTER TABLE seichies DROP FOREIGN KEY seichies_ibfk_1;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE seichies MODIFY user_id INT UNSIGNED NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE seichies
+    ADD CONSTRAINT seichies_user_id_fk FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE seichies DROP FOREIGN KEY seichies_user_id_fk;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DELETE FROM seichies WHERE user_id IS NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE seichies MODIFY user_id INT UNSIGNED NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE seichies
+    ADD CONSTRAINT seichies_ibfk_1 FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE;
+-- +goose StatementEnd
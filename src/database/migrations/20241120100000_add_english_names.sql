@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE genres
+    ADD COLUMN genre_name_en VARCHAR(255) AFTER genre_name;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE contents
+    ADD COLUMN content_name_en VARCHAR(255) AFTER content_name;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE contents
+    DROP COLUMN content_name_en;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE genres
+    DROP COLUMN genre_name_en;
+-- +goose StatementEnd
@@ -0,0 +1,69 @@
+// Seicheese-Backend/src/internal/apperror/apperror.go
+
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error はAPIで返すエラー
+// Code はクライアントが判定に使う固定の文字列、Message はログ用のメッセージ
+// （レスポンスのメッセージはCodeをもとにi18nのカタログからリクエストの言語で作る）
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+	Details interface{}
+	// 原因となったエラー（ログ出力用でレスポンスには含めない）
+	Err error
+}
+
+// New はエラーを作成
+func New(status int, code, message string) *Error {
+	return &Error{
+		Code:    code,
+		Status:  status,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is はコードが同じエラーを同一とみなす（errors.Is(err, apperror.ErrUserNotFound) のように使う）
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap は原因となったエラーを付与したコピーを返す
+func (e *Error) Wrap(err error) *Error {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+// WithDetails は追加情報を付与したコピーを返す
+func (e *Error) WithDetails(details interface{}) *Error {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+// As はerrを*Errorとして取り出す
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
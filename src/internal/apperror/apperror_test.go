@@ -0,0 +1,132 @@
+package apperror_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"seicheese/internal/apperror"
+
+	"github.com/labstack/echo/v4"
+)
+
+// handle はHTTPErrorHandlerでerrを返したときのレスポンスを返す
+func handle(t *testing.T, method, lang string, err error) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, "/api/test", nil)
+	if lang != "" {
+		req.Header.Set("Accept-Language", lang)
+	}
+	rec := httptest.NewRecorder()
+	apperror.HTTPErrorHandler(err, echo.New().NewContext(req, rec))
+	return rec
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		lang        string
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+	}{
+		{"app error", apperror.ErrUserNotFound, "", http.StatusNotFound, "USER_NOT_FOUND", "ユーザーが見つかりません"},
+		{"app error in english", apperror.ErrUserNotFound, "en", http.StatusNotFound, "USER_NOT_FOUND", "The user was not found."},
+		{"wrapped app error", fmt.Errorf("load user: %w", apperror.ErrUserNotFound.Wrap(errors.New("no rows"))), "", http.StatusNotFound, "USER_NOT_FOUND", "ユーザーが見つかりません"},
+		// カタログにないコードはMessageをそのまま使う
+		{"code without translation", apperror.New(http.StatusConflict, "CUSTOM_CONFLICT", "競合しました"), "en", http.StatusConflict, "CUSTOM_CONFLICT", "競合しました"},
+		{"echo http error", echo.NewHTTPError(http.StatusMethodNotAllowed), "", http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "許可されていないメソッドです"},
+		// カタログにないステータスはHTTPのステータス文からコードを作る
+		{"echo http error without translation", echo.NewHTTPError(http.StatusTeapot), "", http.StatusTeapot, "IM_A_TEAPOT", "I'm a teapot"},
+		{"echo not found", echo.ErrNotFound, "", http.StatusNotFound, "NOT_FOUND", "リソースが見つかりません"},
+		{"echo server error", echo.NewHTTPError(http.StatusServiceUnavailable, "database is down"), "", http.StatusInternalServerError, "INTERNAL_ERROR", "サーバーでエラーが発生しました"},
+		// 想定外のエラーは内容を返さずに内部エラーにする
+		{"unknown error", errors.New("dial tcp 10.0.0.1:3306: connection refused"), "", http.StatusInternalServerError, "INTERNAL_ERROR", "サーバーでエラーが発生しました"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := handle(t, http.MethodGet, tt.lang, tt.err)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			var resp apperror.Response
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode %s: %v", rec.Body.String(), err)
+			}
+			if resp.Error.Code != tt.wantCode || resp.Error.Message != tt.wantMessage {
+				t.Errorf("error = %+v, want %s %q", resp.Error, tt.wantCode, tt.wantMessage)
+			}
+			if strings.Contains(rec.Body.String(), "10.0.0.1") || strings.Contains(rec.Body.String(), "database is down") {
+				t.Errorf("response leaks the cause: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHTTPErrorHandlerDetails(t *testing.T) {
+	err := apperror.ErrValidationFailed.WithDetails(apperror.FieldErrors{
+		{Field: "name", Code: "FIELD_TOO_LONG", Params: map[string]interface{}{"max": 50}},
+		{Field: "custom", Code: "CUSTOM_FIELD_CODE", Message: "custom message"},
+	})
+	rec := handle(t, http.MethodPost, "en", err)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	var resp struct {
+		Error struct {
+			Code    string                `json:"code"`
+			Details []apperror.FieldError `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error.Code != "VALIDATION_FAILED" || len(resp.Error.Details) != 2 {
+		t.Fatalf("response = %s", rec.Body.String())
+	}
+	if got := resp.Error.Details[0].Message; !strings.Contains(got, "50") {
+		t.Errorf("name message = %q, want the max length in the message", got)
+	}
+	if got := resp.Error.Details[1].Message; got != "custom message" {
+		t.Errorf("custom message = %q, want the original message for an unknown code", got)
+	}
+
+	// HEADリクエストには本文を返さない
+	rec = handle(t, http.MethodHead, "", apperror.ErrUserNotFound)
+	if rec.Code != http.StatusNotFound || rec.Body.Len() != 0 {
+		t.Errorf("HEAD = %d %q, want 404 without body", rec.Code, rec.Body.String())
+	}
+}
+
+func TestError(t *testing.T) {
+	cause := errors.New("no rows")
+	err := apperror.ErrUserNotFound.Wrap(cause)
+
+	// コードが同じエラーは同一とみなし、原因のエラーも取り出せる
+	if !errors.Is(err, apperror.ErrUserNotFound) || !errors.Is(err, cause) {
+		t.Errorf("errors.Is(%v) failed", err)
+	}
+	if errors.Is(err, apperror.ErrUserExists) {
+		t.Errorf("errors.Is(%v, ErrUserExists) = true", err)
+	}
+	if apperror.ErrUserNotFound.Err != nil {
+		t.Error("Wrap modified the shared error")
+	}
+	if got := err.Error(); got != "USER_NOT_FOUND: ユーザーが見つかりません: no rows" {
+		t.Errorf("Error() = %q", got)
+	}
+
+	if _, ok := apperror.As(fmt.Errorf("wrapped: %w", err)); !ok {
+		t.Error("As(wrapped) = false, want true")
+	}
+	if _, ok := apperror.As(cause); ok {
+		t.Error("As(plain error) = true, want false")
+	}
+}
@@ -0,0 +1,55 @@
+// Seicheese-Backend/src/internal/apperror/errors.go
+
+package apperror
+
+import "net/http"
+
+// 共通
+var (
+	ErrInvalidRequest = New(http.StatusBadRequest, "INVALID_REQUEST", "不正なリクエスト形式です")
+	// Details には FieldErrors を設定する
+	ErrValidationFailed = New(http.StatusBadRequest, "VALIDATION_FAILED", "入力内容に誤りがあります")
+	ErrNotFound         = New(http.StatusNotFound, "NOT_FOUND", "リソースが見つかりません")
+	ErrInternal         = New(http.StatusInternalServerError, "INTERNAL_ERROR", "サーバーでエラーが発生しました")
+	// Details には {"retry_after": 秒数} を設定する
+	ErrTooManyRequests = New(http.StatusTooManyRequests, "TOO_MANY_REQUESTS", "リクエストが多すぎます。しばらくしてから再度お試しください")
+)
+
+// 認証
+var (
+	ErrTokenRequired      = New(http.StatusUnauthorized, "TOKEN_REQUIRED", "認証トークンがありません")
+	ErrInvalidTokenFormat = New(http.StatusUnauthorized, "INVALID_TOKEN_FORMAT", "トークン形式が無効です")
+	ErrInvalidToken       = New(http.StatusUnauthorized, "INVALID_TOKEN", "無効なトークンです")
+	ErrVersionRequired    = New(http.StatusBadRequest, "VERSION_REQUIRED", "バージョン情報が必要です")
+	ErrUnsupportedVersion = New(http.StatusBadRequest, "UNSUPPORTED_APP_VERSION", "サポートされていないアプリバージョンです")
+	// Details には {"min_version": 最低サポートバージョン, "store_urls": {プラットフォーム: URL}} を設定する
+	ErrUpgradeRequired = New(http.StatusUpgradeRequired, "UPGRADE_REQUIRED", "アプリのアップデートが必要です")
+)
+
+// ユーザー
+var (
+	ErrInvalidUserID     = New(http.StatusBadRequest, "INVALID_USER_ID", "ユーザーIDが不正です")
+	ErrUserNotFound      = New(http.StatusNotFound, "USER_NOT_FOUND", "ユーザーが見つかりません")
+	ErrUserNotRegistered = New(http.StatusForbidden, "USER_NOT_REGISTERED", "ユーザー登録が完了していません。サインアップしてください。")
+	ErrUserExists        = New(http.StatusConflict, "USER_EXISTS", "既に登録済みのユーザーです。サインインしてください。")
+
+	ErrDisplayNameRequired      = New(http.StatusBadRequest, "DISPLAY_NAME_REQUIRED", "表示名を入力してください")
+	ErrDisplayNameTooLong       = New(http.StatusBadRequest, "DISPLAY_NAME_TOO_LONG", "表示名が長すぎます")
+	ErrDisplayNameInvalidChars  = New(http.StatusBadRequest, "DISPLAY_NAME_INVALID_CHARACTERS", "表示名に使用できない文字が含まれています")
+	ErrDisplayNameInappropriate = New(http.StatusBadRequest, "DISPLAY_NAME_INAPPROPRIATE", "表示名に不適切な表現が含まれています")
+	ErrBioTooLong               = New(http.StatusBadRequest, "BIO_TOO_LONG", "自己紹介が長すぎます")
+	ErrBioInappropriate         = New(http.StatusBadRequest, "BIO_INAPPROPRIATE", "自己紹介に不適切な表現が含まれています")
+	ErrTooManyFavoriteGenres    = New(http.StatusBadRequest, "TOO_MANY_FAVORITE_GENRES", "お気に入りジャンルが多すぎます")
+	ErrGenreNotFound            = New(http.StatusBadRequest, "GENRE_NOT_FOUND", "存在しないジャンルが含まれています")
+
+	ErrAvatarRequired        = New(http.StatusBadRequest, "AVATAR_REQUIRED", "画像ファイルが必要です")
+	ErrAvatarTooLarge        = New(http.StatusRequestEntityTooLarge, "AVATAR_TOO_LARGE", "画像サイズが大きすぎます（上限5MB）")
+	ErrAvatarUnreadable      = New(http.StatusBadRequest, "AVATAR_UNREADABLE", "画像ファイルを読み込めません")
+	ErrAvatarUnsupportedType = New(http.StatusUnsupportedMediaType, "AVATAR_UNSUPPORTED_TYPE", "対応していない画像形式です（JPEG・PNG・WebPのみ）")
+)
+
+// 聖地・作品
+var (
+	ErrGeocodingFailed = New(http.StatusBadGateway, "GEOCODING_FAILED", "住所の取得に失敗しました")
+	ErrAddressNotFound = New(http.StatusBadRequest, "ADDRESS_NOT_FOUND", "指定された位置の住所が見つかりません")
+)
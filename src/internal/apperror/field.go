@@ -0,0 +1,28 @@
+// Seicheese-Backend/src/internal/apperror/field.go
+
+package apperror
+
+import "seicheese/internal/i18n"
+
+// FieldError はリクエストの項目ごとの検証エラー
+type FieldError struct {
+	Field   string                 `json:"field"`
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+}
+
+// FieldErrors は ErrValidationFailed のDetailsに設定する
+type FieldErrors []FieldError
+
+// localize はメッセージを指定言語で設定したコピーを返す
+func (f FieldErrors) localize(lang i18n.Lang) FieldErrors {
+	localized := make(FieldErrors, len(f))
+	for i, field := range f {
+		localized[i] = field
+		if message := i18n.Message(lang, field.Code, field.Params); message != "" {
+			localized[i].Message = message
+		}
+	}
+	return localized
+}
@@ -0,0 +1,115 @@
+// Seicheese-Backend/src/internal/apperror/handler.go
+
+package apperror
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"seicheese/internal/i18n"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Response はエラー時のレスポンスの形式（すべてのAPIで共通）
+//
+//	{"error": {"code": "USER_NOT_FOUND", "message": "...", "details": {...}}}
+type Response struct {
+	Error Body `json:"error"`
+}
+
+type Body struct {
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// HTTPErrorHandler はハンドラやミドルウェアが返したエラーを共通の形式で返す
+// e.HTTPErrorHandler に設定して使う
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	appErr := fromError(err)
+	if appErr.Status >= http.StatusInternalServerError {
+		slog.ErrorContext(c.Request().Context(), "request failed",
+			"method", c.Request().Method,
+			"path", c.Request().URL.Path,
+			"code", appErr.Code,
+			"error", err,
+		)
+	}
+
+	var sendErr error
+	if c.Request().Method == http.MethodHead {
+		sendErr = c.NoContent(appErr.Status)
+	} else {
+		lang := i18n.FromContext(c)
+		sendErr = c.JSON(appErr.Status, Response{Error: Body{
+			Code:    appErr.Code,
+			Message: localize(lang, appErr),
+			Details: localizeDetails(lang, appErr.Details),
+		}})
+	}
+	if sendErr != nil {
+		slog.ErrorContext(c.Request().Context(), "failed to send error response", "error", sendErr)
+	}
+}
+
+// localize はリクエストの言語でメッセージを返す（カタログにないコードはMessageをそのまま使う）
+// Detailsがmap[string]interface{}の場合、メッセージ中の {name} を置き換える
+func localize(lang i18n.Lang, appErr *Error) string {
+	params, _ := appErr.Details.(map[string]interface{})
+	if message := i18n.Message(lang, appErr.Code, params); message != "" {
+		return message
+	}
+	return appErr.Message
+}
+
+// localizeDetails は項目ごとのエラーのメッセージをリクエストの言語で設定する
+func localizeDetails(lang i18n.Lang, details interface{}) interface{} {
+	if fields, ok := details.(FieldErrors); ok {
+		return fields.localize(lang)
+	}
+	return details
+}
+
+// fromError は任意のエラーを*Errorに変換する
+// *Error以外のエラーは想定外のエラーとして内部エラーにする
+func fromError(err error) *Error {
+	if appErr, ok := As(err); ok {
+		return appErr
+	}
+
+	// ルーティングやechoのミドルウェアが返すエラー
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		if httpErr.Code >= http.StatusInternalServerError {
+			return ErrInternal.Wrap(err)
+		}
+		message, ok := httpErr.Message.(string)
+		if !ok || message == "" {
+			message = http.StatusText(httpErr.Code)
+		}
+		return &Error{
+			Code:    statusCode(httpErr.Code),
+			Status:  httpErr.Code,
+			Message: message,
+			Err:     err,
+		}
+	}
+
+	return ErrInternal.Wrap(err)
+}
+
+// statusCode はHTTPステータスからコードを作る（404 → "NOT_FOUND"）
+func statusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "UNKNOWN_ERROR"
+	}
+	return strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+}
@@ -0,0 +1,165 @@
+package appversion_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"seicheese/internal/appversion"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "1.2.3", want: "1.2.3"},
+		{in: "0.1.0", want: "0.1.0"},
+		{in: "1.2.3-beta.1", want: "1.2.3-beta.1"},
+		{in: "1.2.3+456", want: "1.2.3"},
+		{in: "1.2.3-rc.1+456", want: "1.2.3-rc.1"},
+		{in: "1.2", wantErr: true},
+		{in: "1.2.3.4", wantErr: true},
+		{in: "v1.2.3", wantErr: true},
+		{in: "1.02.3", wantErr: true},
+		{in: "1.2.x", wantErr: true},
+		{in: "1.2.3-", wantErr: true},
+		{in: "1.2.3-beta..1", wantErr: true},
+		{in: "1.2.3-01", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		v, err := appversion.Parse(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %v, want error", tt.in, v)
+			}
+			continue
+		}
+		if err != nil || v.String() != tt.want {
+			t.Errorf("Parse(%q) = %v, %v; want %s", tt.in, v, err, tt.want)
+		}
+	}
+}
+
+// セマンティックバージョニングの仕様にある優先順位の例
+func TestCompare(t *testing.T) {
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11",
+		"1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "1.10.0", "2.0.0",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			a, _ := appversion.Parse(ordered[i])
+			b, _ := appversion.Parse(ordered[j])
+			got := a.Compare(b)
+			if (i < j && got >= 0) || (i == j && got != 0) || (i > j && got <= 0) {
+				t.Errorf("Compare(%s, %s) = %d", ordered[i], ordered[j], got)
+			}
+		}
+	}
+}
+
+func newPolicy(t *testing.T, config *appversion.Config) *appversion.Policy {
+	t.Helper()
+	policy, err := appversion.NewPolicy(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policy
+}
+
+func TestCheck(t *testing.T) {
+	config := &appversion.Config{
+		MinSupported: "1.0.0",
+		Recommended:  "1.2.0",
+		IOS:          appversion.PlatformConfig{MinSupported: "1.1.0", StoreURL: "https://apps.apple.com/jp/app/id0000000000"},
+		Android:      appversion.PlatformConfig{Recommended: "1.3.0", StoreURL: "https://play.google.com/store/apps/details?id=jp.seicheese"},
+	}
+	policy := newPolicy(t, config)
+
+	tests := []struct {
+		platform, version string
+		want              appversion.Status
+		wantMin           string
+		wantRecommended   string
+	}{
+		{"", "1.2.0", appversion.Supported, "1.0.0", "1.2.0"},
+		{"", "1.1.9", appversion.UpdateRecommended, "1.0.0", "1.2.0"},
+		{"", "0.9.9", appversion.UpgradeRequired, "1.0.0", "1.2.0"},
+		{"", "1.0.0-rc.1", appversion.UpgradeRequired, "1.0.0", "1.2.0"},
+		{"ios", "1.0.5", appversion.UpgradeRequired, "1.1.0", "1.2.0"},
+		{"iOS", "1.1.0", appversion.UpdateRecommended, "1.1.0", "1.2.0"},
+		{"android", "1.2.0", appversion.UpdateRecommended, "1.0.0", "1.3.0"},
+		{"android", "1.3.0", appversion.Supported, "1.0.0", "1.3.0"},
+		{"web", "0.9.9", appversion.UpgradeRequired, "1.0.0", "1.2.0"},
+	}
+	for _, tt := range tests {
+		result, err := policy.Check(tt.platform, tt.version)
+		if err != nil {
+			t.Fatalf("Check(%q, %q): %v", tt.platform, tt.version, err)
+		}
+		if result.Status != tt.want || result.MinSupported != tt.wantMin || result.Recommended != tt.wantRecommended {
+			t.Errorf("Check(%q, %q) = %+v, want status %d, min %s, recommended %s", tt.platform, tt.version, result, tt.want, tt.wantMin, tt.wantRecommended)
+		}
+	}
+
+	// ストアURLはプラットフォームが分かる場合はそのプラットフォームだけ返す
+	result, _ := policy.Check("ios", "0.1.0")
+	if want := map[string]string{"ios": config.IOS.StoreURL}; !reflect.DeepEqual(result.StoreURLs, want) {
+		t.Errorf("StoreURLs for ios = %v, want %v", result.StoreURLs, want)
+	}
+	result, _ = policy.Check("", "0.1.0")
+	if len(result.StoreURLs) != 2 {
+		t.Errorf("StoreURLs for unknown platform = %v, want both", result.StoreURLs)
+	}
+
+	if _, err := policy.Check("ios", "1.1"); err == nil {
+		t.Error("malformed version: expected error")
+	}
+
+	// プレリリース版を正式版とみなす
+	config.AllowPrerelease = true
+	result, err := newPolicy(t, config).Check("", "1.0.0-rc.1")
+	if err != nil || result.Status != appversion.UpdateRecommended {
+		t.Errorf("pre-release with AllowPrerelease = %+v, %v; want supported", result, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  appversion.Config
+		wantErr string
+	}{
+		{"valid", appversion.Config{MinSupported: "0.1.0"}, ""},
+		{"missing min", appversion.Config{}, "APP_MIN_SUPPORTED_VERSION is required"},
+		{"malformed min", appversion.Config{MinSupported: "0.1"}, "APP_MIN_SUPPORTED_VERSION"},
+		{"malformed recommended", appversion.Config{MinSupported: "0.1.0", Recommended: "latest"}, "APP_RECOMMENDED_VERSION"},
+		{"recommended older than min", appversion.Config{MinSupported: "1.0.0", Recommended: "0.9.0"}, "older than min supported"},
+		{"platform min newer than recommended", appversion.Config{
+			MinSupported: "1.0.0",
+			Recommended:  "1.1.0",
+			Android:      appversion.PlatformConfig{MinSupported: "1.2.0"},
+		}, "app_version.android"},
+		{"store url without scheme", appversion.Config{
+			MinSupported: "1.0.0",
+			IOS:          appversion.PlatformConfig{StoreURL: "apps.apple.com/jp/app/id0000000000"},
+		}, "app_version.ios.store_url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
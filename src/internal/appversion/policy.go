@@ -0,0 +1,207 @@
+package appversion
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// アプリが送るヘッダー
+const (
+	// HeaderAppVersion はアプリのバージョン（例: 1.2.0）
+	HeaderAppVersion = "X-App-Version"
+	// HeaderAppPlatform はアプリのプラットフォーム（ios または android）
+	HeaderAppPlatform = "X-App-Platform"
+	// HeaderRecommendedVersion は推奨バージョンより古い場合にレスポンスで返す推奨バージョン
+	HeaderRecommendedVersion = "X-App-Recommended-Version"
+)
+
+// プラットフォーム
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+)
+
+// プラットフォームごとの設定構造体
+type PlatformConfig struct {
+	// 最低サポートバージョン（空の場合は共通の値）
+	MinSupported string `yaml:"min_supported"`
+	// 推奨バージョン（空の場合は共通の値）
+	Recommended string `yaml:"recommended"`
+	// アップデートを促すストアのURL
+	StoreURL string `yaml:"store_url"`
+}
+
+// バージョンの方針の設定構造体
+type Config struct {
+	// 最低サポートバージョン（これより古いアプリにはUPGRADE_REQUIREDを返す）
+	MinSupported string `yaml:"min_supported"`
+	// 推奨バージョン（これより古いアプリにはX-App-Recommended-Versionヘッダーでアップデートを促す、空の場合は促さない）
+	Recommended string `yaml:"recommended"`
+	// プレリリース版（1.2.0-beta.1など）を同じバージョンの正式版とみなすか
+	AllowPrerelease bool `yaml:"allow_prerelease"`
+	// X-App-Versionヘッダーのないリクエストを拒否するか
+	RequireHeader bool `yaml:"require_header"`
+	// プラットフォームごとの設定（X-App-Platformで選ぶ）
+	IOS     PlatformConfig `yaml:"ios"`
+	Android PlatformConfig `yaml:"android"`
+}
+
+// Validate はバージョンの形式と、推奨バージョンが最低サポートバージョン以上であることを検証する
+func (c *Config) Validate() error {
+	_, err := NewPolicy(c)
+	return err
+}
+
+// Status はバージョンの確認結果
+type Status int
+
+const (
+	// Supported はサポート対象で推奨バージョン以上
+	Supported Status = iota
+	// UpdateRecommended はサポート対象だが推奨バージョンより古い
+	UpdateRecommended
+	// UpgradeRequired は最低サポートバージョンより古い
+	UpgradeRequired
+)
+
+// Result はCheckの結果
+type Result struct {
+	Status       Status
+	MinSupported string
+	// 推奨バージョン（設定していない場合は空）
+	Recommended string
+	// アップデート先のストアURL（プラットフォームが不明の場合は設定したすべて）
+	StoreURLs map[string]string
+}
+
+type platformPolicy struct {
+	minSupported Version
+	recommended  *Version
+	storeURL     string
+}
+
+// Policy はConfigを解析したバージョンの方針
+type Policy struct {
+	allowPrerelease bool
+	requireHeader   bool
+	// キーはプラットフォーム（""は共通）
+	platforms map[string]platformPolicy
+}
+
+// NewPolicy は設定からPolicyを作成する
+func NewPolicy(config *Config) (*Policy, error) {
+	var errs []error
+	parse := func(name, value string) *Version {
+		if value == "" {
+			return nil
+		}
+		v, err := Parse(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			return nil
+		}
+		return &v
+	}
+
+	p := &Policy{
+		allowPrerelease: config.AllowPrerelease,
+		requireHeader:   config.RequireHeader,
+		platforms:       map[string]platformPolicy{},
+	}
+
+	minSupported := parse("APP_MIN_SUPPORTED_VERSION", config.MinSupported)
+	if minSupported == nil && config.MinSupported == "" {
+		errs = append(errs, errors.New("APP_MIN_SUPPORTED_VERSION is required"))
+	}
+	recommended := parse("APP_RECOMMENDED_VERSION", config.Recommended)
+
+	for _, platform := range []struct {
+		name   string
+		config PlatformConfig
+	}{
+		{"", PlatformConfig{}},
+		{PlatformIOS, config.IOS},
+		{PlatformAndroid, config.Android},
+	} {
+		prefix := "app_version"
+		if platform.name != "" {
+			prefix += "." + platform.name
+		}
+		if url := platform.config.StoreURL; url != "" && !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+			errs = append(errs, fmt.Errorf("%s.store_url must start with http:// or https://: %q", prefix, url))
+		}
+
+		pp := platformPolicy{recommended: recommended, storeURL: platform.config.StoreURL}
+		if minSupported != nil {
+			pp.minSupported = *minSupported
+		}
+		if v := parse(prefix+".min_supported", platform.config.MinSupported); v != nil {
+			pp.minSupported = *v
+		}
+		if v := parse(prefix+".recommended", platform.config.Recommended); v != nil {
+			pp.recommended = v
+		}
+		if pp.recommended != nil && pp.recommended.Compare(pp.minSupported) < 0 {
+			errs = append(errs, fmt.Errorf("%s: recommended version %s is older than min supported version %s", prefix, pp.recommended, pp.minSupported))
+		}
+		p.platforms[platform.name] = pp
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// RequireHeader はX-App-Versionヘッダーのないリクエストを拒否するか
+func (p *Policy) RequireHeader() bool {
+	return p.requireHeader
+}
+
+// Check はplatformのアプリのversionがサポート対象か確認する
+// platformが空または不明な場合は共通の設定を使う。versionの形式が不正な場合はエラーを返す
+func (p *Policy) Check(platform, version string) (Result, error) {
+	v, err := Parse(version)
+	if err != nil {
+		return Result{}, err
+	}
+	if p.allowPrerelease {
+		v = v.Release()
+	}
+
+	platform = strings.ToLower(platform)
+	pp, ok := p.platforms[platform]
+	if !ok {
+		platform = ""
+		pp = p.platforms[""]
+	}
+
+	result := Result{
+		Status:       Supported,
+		MinSupported: pp.minSupported.String(),
+		StoreURLs:    p.storeURLs(platform),
+	}
+	if pp.recommended != nil {
+		result.Recommended = pp.recommended.String()
+	}
+
+	switch {
+	case v.Compare(pp.minSupported) < 0:
+		result.Status = UpgradeRequired
+	case pp.recommended != nil && v.Compare(*pp.recommended) < 0:
+		result.Status = UpdateRecommended
+	}
+	return result, nil
+}
+
+// storeURLs はplatformのストアURL（空の場合は設定したすべて）
+func (p *Policy) storeURLs(platform string) map[string]string {
+	urls := map[string]string{}
+	for name, pp := range p.platforms {
+		if name != "" && pp.storeURL != "" && (platform == "" || platform == name) {
+			urls[name] = pp.storeURL
+		}
+	}
+	return urls
+}
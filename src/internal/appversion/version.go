@@ -0,0 +1,135 @@
+// Package appversion はアプリのバージョンを設定の方針（最低サポート・推奨バージョン）と比較する
+//
+// バージョンはセマンティックバージョニング（MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD]）で比較する
+// 1.2.0-beta.1 は 1.2.0 より古いものとして扱う（AllowPrereleaseで同じとみなすこともできる）
+package appversion
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version はセマンティックバージョン
+type Version struct {
+	Major, Minor, Patch int
+	// プレリリースの識別子（1.2.0-beta.1 の場合は ["beta", "1"]）
+	Prerelease []string
+}
+
+// Parse はバージョン文字列を解析する（ビルドメタデータは比較に使わないため捨てる）
+func Parse(s string) (Version, error) {
+	core, _, _ := strings.Cut(s, "+")
+	core, prerelease, hasPrerelease := strings.Cut(core, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("version must be MAJOR.MINOR.PATCH: %q", s)
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := parseNumber(part)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		numbers[i] = n
+	}
+
+	v := Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}
+	if hasPrerelease {
+		for _, id := range strings.Split(prerelease, ".") {
+			if id == "" {
+				return Version{}, fmt.Errorf("invalid version %q: empty pre-release identifier", s)
+			}
+			if isNumeric(id) {
+				if _, err := parseNumber(id); err != nil {
+					return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+				}
+			}
+			v.Prerelease = append(v.Prerelease, id)
+		}
+	}
+	return v, nil
+}
+
+// parseNumber は先頭に0のない数値を解析する
+func parseNumber(s string) (int, error) {
+	if !isNumeric(s) {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("%q has a leading zero", s)
+	}
+	return strconv.Atoi(s)
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPrerelease はプレリリース版かどうか
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Release はプレリリースの識別子を除いたバージョン
+func (v Version) Release() Version {
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
+
+// Compare はvがwより古い場合は負、同じ場合は0、新しい場合は正の値を返す
+func (v Version) Compare(w Version) int {
+	for _, d := range []int{v.Major - w.Major, v.Minor - w.Minor, v.Patch - w.Patch} {
+		if d != 0 {
+			return d
+		}
+	}
+
+	// プレリリース版は正式版より古い
+	switch {
+	case !v.IsPrerelease() && !w.IsPrerelease():
+		return 0
+	case !v.IsPrerelease():
+		return 1
+	case !w.IsPrerelease():
+		return -1
+	}
+	for i := 0; i < len(v.Prerelease) && i < len(w.Prerelease); i++ {
+		if c := compareIdentifier(v.Prerelease[i], w.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return len(v.Prerelease) - len(w.Prerelease)
+}
+
+// compareIdentifier は数値同士は数値として、それ以外は文字列として比較する（数値は文字列より古い）
+func compareIdentifier(a, b string) int {
+	aNumeric, bNumeric := isNumeric(a), isNumeric(b)
+	switch {
+	case aNumeric && bNumeric:
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return x - y
+	case aNumeric:
+		return -1
+	case bNumeric:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.IsPrerelease() {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	return s
+}
@@ -0,0 +1,168 @@
+// Seicheese-Backend/src/internal/auth/cache.go
+
+package auth
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"seicheese/internal/metrics"
+
+	firebaseauth "firebase.google.com/go/v4/auth"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ Provider = (*CachingProvider)(nil)
+
+// CachingProvider は検証済みのトークンをトークンの有効期限（exp）まで保持し、同じトークンの署名の検証を省くProvider
+// キーはトークンのSHA-256で、トークンそのものは保持しない
+// 保持する件数を超えた場合は最も長く使われていないトークンから破棄する（検証に失敗したトークンは保持しない）
+//
+// revocationCheckIntervalが0より大きい場合は、VerifyIDTokenAndCheckRevokedで失効も確認し、
+// トークンごとに最後の確認からその間隔が過ぎたら確認し直す（0の場合は失効を確認しない）
+type CachingProvider struct {
+	provider                Provider
+	size                    int
+	revocationCheckInterval time.Duration
+
+	// 現在時刻（nilの場合はtime.Now、テスト用）
+	Now func() time.Time
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*list.Element
+	order   *list.List // 先頭ほど最近使われたトークン
+}
+
+type tokenEntry struct {
+	key       [sha256.Size]byte
+	token     *firebaseauth.Token
+	expires   time.Time
+	checkedAt time.Time
+}
+
+// NewCachingProvider はproviderで検証したトークンを最大size件保持するProviderを作成
+// sizeが0の場合は保持せず、revocationCheckIntervalが0より大きければ毎回失効を確認する
+func NewCachingProvider(provider Provider, size int, revocationCheckInterval time.Duration) *CachingProvider {
+	return &CachingProvider{
+		provider:                provider,
+		size:                    size,
+		revocationCheckInterval: revocationCheckInterval,
+		entries:                 map[[sha256.Size]byte]*list.Element{},
+		order:                   list.New(),
+	}
+}
+
+// VerifyIDToken は保持しているトークンの場合は検証せずに返す
+// 返すトークンは他のリクエストと共有されるため、書き換えないこと
+func (p *CachingProvider) VerifyIDToken(ctx context.Context, idToken string) (*firebaseauth.Token, error) {
+	key := sha256.Sum256([]byte(idToken))
+	now := p.now()
+
+	p.mu.Lock()
+	if element, ok := p.entries[key]; ok {
+		entry := element.Value.(*tokenEntry)
+		switch {
+		case !now.Before(entry.expires):
+			p.remove(element)
+		case p.revocationCheckInterval > 0 && now.Sub(entry.checkedAt) >= p.revocationCheckInterval:
+			// 失効を確認し直すため検証する
+		default:
+			p.order.MoveToFront(element)
+			token := entry.token
+			p.mu.Unlock()
+			observeTokenCache(ctx, true)
+			return token, nil
+		}
+	}
+	p.mu.Unlock()
+	observeTokenCache(ctx, false)
+
+	token, err := p.verify(ctx, idToken)
+	if err != nil {
+		// 失効したトークンを保持し続けないよう破棄する
+		p.mu.Lock()
+		if element, ok := p.entries[key]; ok {
+			p.remove(element)
+		}
+		p.mu.Unlock()
+		return nil, err
+	}
+
+	p.store(key, token, now)
+	return token, nil
+}
+
+func (p *CachingProvider) verify(ctx context.Context, idToken string) (*firebaseauth.Token, error) {
+	if p.revocationCheckInterval > 0 {
+		return verifyAndCheckRevoked(ctx, p.provider, idToken)
+	}
+	return p.provider.VerifyIDToken(ctx, idToken)
+}
+
+func (p *CachingProvider) store(key [sha256.Size]byte, token *firebaseauth.Token, now time.Time) {
+	if p.size <= 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry := &tokenEntry{key: key, token: token, expires: time.Unix(token.Expires, 0), checkedAt: now}
+	if element, ok := p.entries[key]; ok {
+		// 失効の確認し直し、または同じトークンの検証が同時に行われた場合
+		element.Value = entry
+		p.order.MoveToFront(element)
+		return
+	}
+	p.entries[key] = p.order.PushFront(entry)
+	for p.order.Len() > p.size {
+		p.remove(p.order.Back())
+	}
+}
+
+// DeleteUser はユーザーを削除し、そのユーザーの保持しているトークンを破棄する
+func (p *CachingProvider) DeleteUser(ctx context.Context, uid string) error {
+	if err := p.provider.DeleteUser(ctx, uid); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for element := p.order.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*tokenEntry).token.UID == uid {
+			p.remove(element)
+		}
+		element = next
+	}
+	return nil
+}
+
+// Len は保持しているトークンの数
+func (p *CachingProvider) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.order.Len()
+}
+
+func (p *CachingProvider) remove(element *list.Element) {
+	p.order.Remove(element)
+	delete(p.entries, element.Value.(*tokenEntry).key)
+}
+
+func (p *CachingProvider) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+// observeTokenCache はキャッシュの参照結果をメトリクスと現在のスパンに記録する
+func observeTokenCache(ctx context.Context, hit bool) {
+	metrics.ObserveTokenCache(hit)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("auth.token_cache_hit", hit))
+}
@@ -0,0 +1,192 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"seicheese/internal/auth"
+
+	firebaseauth "firebase.google.com/go/v4/auth"
+)
+
+// countingProvider はトークン文字列をUIDとして検証し、呼び出し回数を数えるauth.Provider
+type countingProvider struct {
+	mu       sync.Mutex
+	verified int
+	checked  int
+	revoked  map[string]bool
+	expires  time.Time
+	deleted  []string
+}
+
+func (p *countingProvider) VerifyIDToken(ctx context.Context, idToken string) (*firebaseauth.Token, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.verified++
+	return p.token(idToken)
+}
+
+func (p *countingProvider) VerifyIDTokenAndCheckRevoked(ctx context.Context, idToken string) (*firebaseauth.Token, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.checked++
+	if p.revoked[idToken] {
+		return nil, errors.New("ID token has been revoked")
+	}
+	return p.token(idToken)
+}
+
+func (p *countingProvider) token(idToken string) (*firebaseauth.Token, error) {
+	if idToken == "invalid" {
+		return nil, errors.New("signature is invalid")
+	}
+	return &firebaseauth.Token{UID: idToken, Expires: p.expires.Unix()}, nil
+}
+
+func (p *countingProvider) DeleteUser(ctx context.Context, uid string) error {
+	p.deleted = append(p.deleted, uid)
+	return nil
+}
+
+func newCachingProvider(size int, interval time.Duration) (*auth.CachingProvider, *countingProvider, *time.Time) {
+	now := testNow
+	provider := &countingProvider{revoked: map[string]bool{}, expires: now.Add(time.Hour)}
+	cache := auth.NewCachingProvider(provider, size, interval)
+	cache.Now = func() time.Time { return now }
+	return cache, provider, &now
+}
+
+func TestCachingProvider(t *testing.T) {
+	ctx := context.Background()
+	cache, provider, now := newCachingProvider(2, 0)
+
+	for i := 0; i < 3; i++ {
+		token, err := cache.VerifyIDToken(ctx, "uid-1")
+		if err != nil || token.UID != "uid-1" {
+			t.Fatalf("VerifyIDToken = %v, %v", token, err)
+		}
+	}
+	if provider.verified != 1 || provider.checked != 0 {
+		t.Errorf("verified %d times and checked revocation %d times, want 1 and 0", provider.verified, provider.checked)
+	}
+
+	// 検証に失敗したトークンは保持しない
+	for i := 0; i < 2; i++ {
+		if _, err := cache.VerifyIDToken(ctx, "invalid"); err == nil {
+			t.Fatal("invalid token: expected error")
+		}
+	}
+	if provider.verified != 3 || cache.Len() != 1 {
+		t.Errorf("verified %d times with %d cached tokens, want 3 and 1", provider.verified, cache.Len())
+	}
+
+	// 最も長く使われていないトークンから破棄する
+	cache.VerifyIDToken(ctx, "uid-2")
+	cache.VerifyIDToken(ctx, "uid-1")
+	cache.VerifyIDToken(ctx, "uid-3")
+	provider.verified = 0
+	cache.VerifyIDToken(ctx, "uid-1")
+	cache.VerifyIDToken(ctx, "uid-3")
+	if provider.verified != 0 {
+		t.Errorf("recently used tokens were verified %d times, want cached", provider.verified)
+	}
+	cache.VerifyIDToken(ctx, "uid-2")
+	if provider.verified != 1 || cache.Len() != 2 {
+		t.Errorf("evicted token verified %d times with %d cached tokens, want 1 and 2", provider.verified, cache.Len())
+	}
+
+	// 有効期限を過ぎたトークンは検証し直す
+	provider.verified = 0
+	*now = now.Add(time.Hour)
+	provider.expires = now.Add(time.Hour)
+	cache.VerifyIDToken(ctx, "uid-2")
+	if provider.verified != 1 {
+		t.Errorf("expired token verified %d times, want 1", provider.verified)
+	}
+}
+
+func TestCachingProviderRevocationCheck(t *testing.T) {
+	ctx := context.Background()
+	cache, provider, now := newCachingProvider(10, 5*time.Minute)
+
+	cache.VerifyIDToken(ctx, "uid-1")
+	*now = now.Add(4 * time.Minute)
+	cache.VerifyIDToken(ctx, "uid-1")
+	if provider.checked != 1 || provider.verified != 0 {
+		t.Errorf("checked revocation %d times and verified %d times within interval, want 1 and 0", provider.checked, provider.verified)
+	}
+
+	// 間隔が過ぎたら確認し直し、失効していれば破棄する
+	provider.revoked["uid-1"] = true
+	*now = now.Add(time.Minute)
+	if _, err := cache.VerifyIDToken(ctx, "uid-1"); err == nil {
+		t.Fatal("revoked token: expected error")
+	}
+	if provider.checked != 2 || cache.Len() != 0 {
+		t.Errorf("checked revocation %d times with %d cached tokens, want 2 and 0", provider.checked, cache.Len())
+	}
+
+	// 保持しない場合は毎回確認する
+	cache, provider, _ = newCachingProvider(0, time.Minute)
+	cache.VerifyIDToken(ctx, "uid-1")
+	cache.VerifyIDToken(ctx, "uid-1")
+	if provider.checked != 2 || cache.Len() != 0 {
+		t.Errorf("without cache checked revocation %d times with %d cached tokens, want 2 and 0", provider.checked, cache.Len())
+	}
+}
+
+func TestCachingProviderDeleteUser(t *testing.T) {
+	ctx := context.Background()
+	cache, provider, _ := newCachingProvider(10, 0)
+
+	cache.VerifyIDToken(ctx, "uid-1")
+	cache.VerifyIDToken(ctx, "uid-2")
+	if err := cache.DeleteUser(ctx, "uid-1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(provider.deleted) != 1 || cache.Len() != 1 {
+		t.Errorf("deleted %v with %d cached tokens, want [uid-1] and 1", provider.deleted, cache.Len())
+	}
+}
+
+// 署名の検証（RS256）とキャッシュのヒットの比較
+//
+//	go test ./internal/auth -run '^$' -bench VerifyIDToken -benchmem
+func BenchmarkVerifyIDToken(b *testing.B) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		b.Fatal(err)
+	}
+	issuer := auth.NewRS256Issuer(key, "seicheese-test")
+
+	tokens := make([]string, 100)
+	for i := range tokens {
+		if tokens[i], err = issuer.Mint(fmt.Sprintf("uid-%d", i), nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	run := func(b *testing.B, provider auth.Provider) {
+		ctx := context.Background()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := provider.VerifyIDToken(ctx, tokens[i%len(tokens)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	b.Run("uncached", func(b *testing.B) {
+		run(b, issuer)
+	})
+	b.Run("cached", func(b *testing.B) {
+		run(b, auth.NewCachingProvider(issuer, len(tokens), 0))
+	})
+}
@@ -0,0 +1,195 @@
+// Seicheese-Backend/src/internal/auth/config.go
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	firebase "firebase.google.com/go/v4"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// 認証モード
+const (
+	// ModeFirebase はFirebase Authenticationでトークンを検証する（本番用）
+	ModeFirebase = "firebase"
+	// ModeLocal はローカル発行のトークンで検証する（テスト・ローカル開発用）
+	ModeLocal = "local"
+)
+
+// 認証設定の構造体
+type Config struct {
+	Mode      string `yaml:"mode"`
+	ProjectID string `yaml:"firebase_project_id"`
+	// ローカル発行時の署名アルゴリズム（HS256 または RS256）
+	LocalAlgorithm string `yaml:"local_algorithm"`
+	// HS256用の共有シークレット
+	LocalSecret string `yaml:"local_secret"`
+	// RS256用の秘密鍵（PEM）のパス
+	LocalPrivateKeyPath string `yaml:"local_private_key_path"`
+	// 検証済みのトークンを保持する件数（0の場合は保持しない）
+	TokenCacheSize int `yaml:"token_cache_size"`
+	// トークンの失効を確認する間隔（0の場合は確認しない、Firebaseモードのみ、TokenCacheSizeが0の場合は使えない）
+	RevocationCheckInterval time.Duration `yaml:"revocation_check_interval"`
+	// Firebase Auth Emulatorのホスト（例: localhost:9099、Firebaseモードのみ、AllowEmulatorが必要）
+	// エミュレーターのトークンは署名がないため、ローカル開発以外では設定しないこと
+	EmulatorHost string `yaml:"emulator_host"`
+	// Firebase Auth Emulatorの使用を許可するか（誤って本番で署名のないトークンを受け付けないよう明示的に有効にする）
+	AllowEmulator bool `yaml:"allow_emulator"`
+	// Identity Platformのテナント（空の場合はテナントに属さないユーザーのトークンだけを受け付ける）
+	TenantID string `yaml:"tenant_id"`
+	// 期待するトークンの発行者（空の場合はhttps://securetoken.google.com/<project-id>）
+	Issuer string `yaml:"issuer"`
+	// 期待するトークンの対象（空の場合はプロジェクトID）
+	Audience string `yaml:"audience"`
+}
+
+// Validate はモードごとに必要な設定がそろっているか検証する
+// FIREBASE_PROJECT_IDがないとトークンの発行者が一致せず、すべてのトークンが無効になるため必須とする
+func (c *Config) Validate() error {
+	if c.TokenCacheSize < 0 {
+		return fmt.Errorf("AUTH_TOKEN_CACHE_SIZE must not be negative: %d", c.TokenCacheSize)
+	}
+	if c.RevocationCheckInterval < 0 {
+		return fmt.Errorf("AUTH_REVOCATION_CHECK_INTERVAL must not be negative: %s", c.RevocationCheckInterval)
+	}
+	// 確認した時刻はキャッシュに保持するため、保持しない場合は間隔を守れず毎回確認することになる
+	if c.RevocationCheckInterval > 0 && c.TokenCacheSize == 0 {
+		return errors.New("AUTH_REVOCATION_CHECK_INTERVAL requires AUTH_TOKEN_CACHE_SIZE greater than 0")
+	}
+
+	if err := c.validateEmulator(); err != nil {
+		return err
+	}
+
+	switch c.Mode {
+	case ModeFirebase:
+		if c.ProjectID == "" {
+			return errors.New("FIREBASE_PROJECT_ID is required when AUTH_MODE=firebase")
+		}
+	case ModeLocal:
+		if c.EmulatorHost != "" {
+			return errors.New("FIREBASE_AUTH_EMULATOR_HOST requires AUTH_MODE=firebase")
+		}
+		switch c.LocalAlgorithm {
+		case jwt.SigningMethodHS256.Alg():
+			if c.LocalSecret == "" {
+				return errors.New("LOCAL_AUTH_SECRET is required for HS256")
+			}
+		case jwt.SigningMethodRS256.Alg():
+			if c.LocalPrivateKeyPath == "" {
+				return errors.New("LOCAL_AUTH_PRIVATE_KEY_PATH is required for RS256")
+			}
+		default:
+			return fmt.Errorf("unsupported LOCAL_AUTH_ALGORITHM: %s", c.LocalAlgorithm)
+		}
+	default:
+		return fmt.Errorf("unknown AUTH_MODE: %s", c.Mode)
+	}
+	return nil
+}
+
+// validateEmulator はFirebase Auth Emulatorを明示的に許可している場合だけ使えるようにする
+// 環境変数の設定漏れなどで本番がエミュレーターのモードになると、誰でも任意のUIDのトークンを作れてしまうため
+func (c *Config) validateEmulator() error {
+	if c.EmulatorHost != "" && !c.AllowEmulator {
+		return errors.New("FIREBASE_AUTH_EMULATOR_HOST is set but AUTH_ALLOW_EMULATOR is not true; the emulator accepts unsigned tokens and must not be used in production")
+	}
+	return nil
+}
+
+// ExpectedIssuer は受け付けるIDトークンのiss（AUTH_ISSUERがない場合はFirebaseと同じhttps://securetoken.google.com/<project-id>）
+// Firebase Auth Emulatorもこの形式で発行する
+func (c *Config) ExpectedIssuer() string {
+	if c.Issuer != "" {
+		return c.Issuer
+	}
+	return "https://securetoken.google.com/" + c.ProjectID
+}
+
+// ExpectedAudience は受け付けるIDトークンのaud（AUTH_AUDIENCEがない場合はプロジェクトID）
+func (c *Config) ExpectedAudience() string {
+	if c.Audience != "" {
+		return c.Audience
+	}
+	return c.ProjectID
+}
+
+// UsesFirebase はFirebaseアプリの初期化が必要かどうか
+func (c *Config) UsesFirebase() bool {
+	return c.Mode == ModeFirebase
+}
+
+// UsesEmulator はFirebase Auth Emulatorを使うかどうか（サービスアカウントの認証情報は不要）
+func (c *Config) UsesEmulator() bool {
+	return c.UsesFirebase() && c.EmulatorHost != ""
+}
+
+// InitializeProvider は設定に応じた認証基盤を初期化（ローカルモードではappはnilでよい）
+func InitializeProvider(ctx context.Context, app *firebase.App, config *Config) (Provider, error) {
+	switch config.Mode {
+	case ModeFirebase:
+		if err := config.validateEmulator(); err != nil {
+			return nil, err
+		}
+		if config.EmulatorHost != "" {
+			// SDKは環境変数でエミュレーターを選ぶため、設定ファイルで指定した場合も反映する
+			if err := os.Setenv(EmulatorHostEnv, config.EmulatorHost); err != nil {
+				return nil, fmt.Errorf("failed to set %s: %v", EmulatorHostEnv, err)
+			}
+		}
+		client, err := app.Auth(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("auth client initialization error: %v", err)
+		}
+
+		var provider Provider = client
+		if config.TenantID != "" {
+			// テナントのクライアントは他のテナントのトークンを拒否する
+			tenant, err := client.TenantManager.AuthForTenant(config.TenantID)
+			if err != nil {
+				return nil, fmt.Errorf("tenant auth client initialization error: %v", err)
+			}
+			provider = tenant
+		}
+		if config.EmulatorHost != "" {
+			// SDKは検証のたびにエミュレーターに問い合わせるため、トークンはここで解析し、ユーザーの削除だけを送る
+			provider = &emulatorProvider{EmulatorVerifier: &EmulatorVerifier{}, UserDeleter: provider}
+		}
+		return provider, nil
+	case ModeLocal:
+		return NewLocalIssuerFromConfig(config)
+	default:
+		return nil, fmt.Errorf("unknown AUTH_MODE: %s", config.Mode)
+	}
+}
+
+// NewLocalIssuerFromConfig は設定からローカル発行者を作成
+func NewLocalIssuerFromConfig(config *Config) (*LocalIssuer, error) {
+	switch config.LocalAlgorithm {
+	case jwt.SigningMethodHS256.Alg():
+		if config.LocalSecret == "" {
+			return nil, fmt.Errorf("LOCAL_AUTH_SECRET is required for HS256")
+		}
+		return NewHS256Issuer([]byte(config.LocalSecret), config.ProjectID).withExpected(config), nil
+	case jwt.SigningMethodRS256.Alg():
+		if config.LocalPrivateKeyPath == "" {
+			return nil, fmt.Errorf("LOCAL_AUTH_PRIVATE_KEY_PATH is required for RS256")
+		}
+		data, err := os.ReadFile(config.LocalPrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key: %v", err)
+		}
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %v", err)
+		}
+		return NewRS256Issuer(key, config.ProjectID).withExpected(config), nil
+	default:
+		return nil, fmt.Errorf("unsupported LOCAL_AUTH_ALGORITHM: %s", config.LocalAlgorithm)
+	}
+}
@@ -0,0 +1,170 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"seicheese/internal/auth"
+)
+
+// emulatorToken はFirebase Auth Emulatorと同じ形式の署名のないトークンを作る
+func emulatorToken(t *testing.T, modify func(claims map[string]interface{})) string {
+	t.Helper()
+	claims := map[string]interface{}{
+		"iss":       testIssuer,
+		"aud":       "seicheese-test",
+		"sub":       "uid-1",
+		"user_id":   "uid-1",
+		"iat":       testNow.Add(-time.Minute).Unix(),
+		"exp":       testNow.Add(time.Hour).Unix(),
+		"auth_time": testNow.Add(-time.Minute).Unix(),
+		"firebase": map[string]interface{}{
+			"sign_in_provider": "password",
+			"identities":       map[string]interface{}{},
+		},
+	}
+	if modify != nil {
+		modify(claims)
+	}
+	return encodeSegment(t, map[string]interface{}{"alg": "none", "typ": "JWT"}) + "." + encodeSegment(t, claims) + "."
+}
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func newEmulatorAuthenticator(config *auth.Config) *auth.Authenticator {
+	authenticator := auth.NewAuthenticator(&auth.EmulatorVerifier{}, config)
+	authenticator.Now = func() time.Time { return testNow }
+	return authenticator
+}
+
+func TestEmulatorVerifier(t *testing.T) {
+	config := &auth.Config{Mode: auth.ModeFirebase, ProjectID: "seicheese-test", EmulatorHost: "localhost:9099", AllowEmulator: true}
+
+	principal, err := newEmulatorAuthenticator(config).Authenticate(context.Background(), emulatorToken(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if principal.UID != "uid-1" || principal.SignInProvider != "password" || principal.Tenant != "" {
+		t.Errorf("principal = %+v, want uid-1 signed in with password", *principal)
+	}
+
+	// 署名のあるトークンは受け付けない
+	signed, err := auth.NewHS256Issuer([]byte("secret"), "seicheese-test").Mint("uid-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{"malformed", "not-a-token", "invalid emulator token"},
+		{"signed", signed, "unexpected algorithm"},
+		{"empty subject", emulatorToken(t, func(claims map[string]interface{}) { delete(claims, "sub") }), "empty subject"},
+		{"missing exp", emulatorToken(t, func(claims map[string]interface{}) { delete(claims, "exp") }), "missing exp"},
+		{"expired", emulatorToken(t, func(claims map[string]interface{}) {
+			claims["exp"] = testNow.Add(-time.Second).Unix()
+		}), "expired"},
+		{"other project", emulatorToken(t, func(claims map[string]interface{}) {
+			claims["iss"] = "https://securetoken.google.com/other-project"
+			claims["aud"] = "other-project"
+		}), "invalid token issuer"},
+		{"other audience", emulatorToken(t, func(claims map[string]interface{}) { claims["aud"] = "other-project" }), "invalid token audience"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newEmulatorAuthenticator(config).Authenticate(context.Background(), tt.token)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEmulatorRequiresOptIn(t *testing.T) {
+	// 署名のないトークンを受け付けるため、ホストの設定だけでは使えない
+	config := &auth.Config{Mode: auth.ModeFirebase, ProjectID: "seicheese-test", EmulatorHost: "localhost:9099"}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "AUTH_ALLOW_EMULATOR") {
+		t.Errorf("Validate without opt-in = %v, want AUTH_ALLOW_EMULATOR error", err)
+	}
+	if _, err := auth.InitializeProvider(context.Background(), nil, config); err == nil || !strings.Contains(err.Error(), "AUTH_ALLOW_EMULATOR") {
+		t.Errorf("InitializeProvider without opt-in = %v, want AUTH_ALLOW_EMULATOR error", err)
+	}
+
+	config.AllowEmulator = true
+	if err := config.Validate(); err != nil {
+		t.Errorf("Validate with opt-in: %v", err)
+	}
+}
+
+func TestEmulatorVerifierTenant(t *testing.T) {
+	tenantToken := emulatorToken(t, func(claims map[string]interface{}) {
+		claims["firebase"].(map[string]interface{})["tenant"] = "tenant-a"
+	})
+	config := &auth.Config{Mode: auth.ModeFirebase, ProjectID: "seicheese-test", TenantID: "tenant-a"}
+
+	principal, err := newEmulatorAuthenticator(config).Authenticate(context.Background(), tenantToken)
+	if err != nil || principal.Tenant != "tenant-a" {
+		t.Fatalf("tenant token = %+v, %v; want tenant-a", principal, err)
+	}
+
+	// 他のテナントやテナントに属さないユーザーのトークンは受け付けない
+	if _, err := newEmulatorAuthenticator(config).Authenticate(context.Background(), emulatorToken(t, nil)); err == nil {
+		t.Error("token without tenant: expected error")
+	}
+	config.TenantID = "tenant-b"
+	if _, err := newEmulatorAuthenticator(config).Authenticate(context.Background(), tenantToken); err == nil {
+		t.Error("token of other tenant: expected error")
+	}
+	config.TenantID = ""
+	if _, err := newEmulatorAuthenticator(config).Authenticate(context.Background(), tenantToken); err == nil {
+		t.Error("tenant token without configured tenant: expected error")
+	}
+}
+
+func TestExpectedIssuerAndAudience(t *testing.T) {
+	config := &auth.Config{
+		Mode:      auth.ModeFirebase,
+		ProjectID: "seicheese-test",
+		Issuer:    "https://issuer.example.com/seicheese",
+		Audience:  "seicheese-api",
+	}
+	token := emulatorToken(t, func(claims map[string]interface{}) {
+		claims["iss"] = config.Issuer
+		claims["aud"] = config.Audience
+	})
+	if _, err := newEmulatorAuthenticator(config).Authenticate(context.Background(), token); err != nil {
+		t.Errorf("token with configured issuer and audience: %v", err)
+	}
+	if _, err := newEmulatorAuthenticator(config).Authenticate(context.Background(), emulatorToken(t, nil)); err == nil {
+		t.Error("token with default issuer: expected error")
+	}
+
+	// ローカル発行者も設定の発行者と対象で発行する
+	config.Mode = auth.ModeLocal
+	config.LocalAlgorithm = "HS256"
+	config.LocalSecret = "secret"
+	issuer, err := auth.NewLocalIssuerFromConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := issuer.Mint("uid-1", map[string]interface{}{"user_id": "uid-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.NewAuthenticator(issuer, config).Authenticate(context.Background(), signed); err != nil {
+		t.Errorf("local token with configured issuer and audience: %v", err)
+	}
+}
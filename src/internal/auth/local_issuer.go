@@ -0,0 +1,164 @@
+// Seicheese-Backend/src/internal/auth/local_issuer.go
+
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"time"
+
+	firebaseauth "firebase.google.com/go/v4/auth"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// ローカル発行トークンのデフォルト有効期間（FirebaseのIDトークンと同じ1時間）
+const defaultLocalTokenTTL = time.Hour
+
+// Firebaseのトークンと同様に、Claimsから除外する標準クレーム
+var standardClaims = []string{"iss", "aud", "exp", "iat", "sub", "uid"}
+
+// LocalIssuer はFirebaseのIDトークンと同じ形式のトークンを発行・検証する
+// テストやローカル開発で、任意のUIDやクレームを持つトークンを作るために使う
+type LocalIssuer struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	issuer    string
+	audience  string
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+// NewHS256Issuer は共有シークレットで署名するLocalIssuerを作成
+func NewHS256Issuer(secret []byte, projectID string) *LocalIssuer {
+	return &LocalIssuer{
+		method:    jwt.SigningMethodHS256,
+		signKey:   secret,
+		verifyKey: secret,
+		issuer:    "https://securetoken.google.com/" + projectID,
+		audience:  projectID,
+		ttl:       defaultLocalTokenTTL,
+		now:       time.Now,
+	}
+}
+
+// NewRS256Issuer はRSA秘密鍵で署名するLocalIssuerを作成
+func NewRS256Issuer(key *rsa.PrivateKey, projectID string) *LocalIssuer {
+	return &LocalIssuer{
+		method:    jwt.SigningMethodRS256,
+		signKey:   key,
+		verifyKey: &key.PublicKey,
+		issuer:    "https://securetoken.google.com/" + projectID,
+		audience:  projectID,
+		ttl:       defaultLocalTokenTTL,
+		now:       time.Now,
+	}
+}
+
+// withExpected は発行者と対象を設定（AUTH_ISSUER、AUTH_AUDIENCE）に合わせる
+func (i *LocalIssuer) withExpected(config *Config) *LocalIssuer {
+	i.issuer = config.ExpectedIssuer()
+	i.audience = config.ExpectedAudience()
+	return i
+}
+
+// Issuer はトークンの発行者（既定ではFirebaseと同じ形式）
+func (i *LocalIssuer) Issuer() string {
+	return i.issuer
+}
+
+// Mint は指定したUIDとクレームを持つトークンを発行する
+// claimsで標準クレーム（exp、iss など）を上書きすることもできる
+func (i *LocalIssuer) Mint(uid string, claims map[string]interface{}) (string, error) {
+	now := i.now()
+	mapClaims := jwt.MapClaims{
+		"iss":       i.Issuer(),
+		"aud":       i.audience,
+		"sub":       uid,
+		"user_id":   uid,
+		"iat":       now.Unix(),
+		"exp":       now.Add(i.ttl).Unix(),
+		"auth_time": now.Unix(),
+		"firebase": map[string]interface{}{
+			"sign_in_provider": "custom",
+			"identities":       map[string]interface{}{},
+		},
+	}
+	for k, v := range claims {
+		mapClaims[k] = v
+	}
+
+	token, err := jwt.NewWithClaims(i.method, mapClaims).SignedString(i.signKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %v", err)
+	}
+	return token, nil
+}
+
+// VerifyIDToken はMintで発行したトークンを検証する
+func (i *LocalIssuer) VerifyIDToken(ctx context.Context, idToken string) (*firebaseauth.Token, error) {
+	parser := jwt.Parser{ValidMethods: []string{i.method.Alg()}}
+	mapClaims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(idToken, mapClaims, func(*jwt.Token) (interface{}, error) {
+		return i.verifyKey, nil
+	}); err != nil {
+		return nil, fmt.Errorf("invalid local token: %v", err)
+	}
+
+	if !mapClaims.VerifyAudience(i.audience, true) {
+		return nil, fmt.Errorf("invalid local token: unexpected audience")
+	}
+	if !mapClaims.VerifyIssuer(i.Issuer(), true) {
+		return nil, fmt.Errorf("invalid local token: unexpected issuer")
+	}
+
+	token := newTokenFromClaims(mapClaims)
+	if token.Subject == "" {
+		return nil, fmt.Errorf("invalid local token: empty subject")
+	}
+	return token, nil
+}
+
+// newTokenFromClaims はIDトークンのクレームからFirebase SDKと同じ形式のトークンを作る
+func newTokenFromClaims(mapClaims jwt.MapClaims) *firebaseauth.Token {
+	issuer, _ := mapClaims["iss"].(string)
+	audience, _ := mapClaims["aud"].(string)
+	subject, _ := mapClaims["sub"].(string)
+	token := &firebaseauth.Token{
+		AuthTime: int64Claim(mapClaims, "auth_time"),
+		Issuer:   issuer,
+		Audience: audience,
+		Expires:  int64Claim(mapClaims, "exp"),
+		IssuedAt: int64Claim(mapClaims, "iat"),
+		Subject:  subject,
+		UID:      subject,
+	}
+	if info, ok := mapClaims["firebase"].(map[string]interface{}); ok {
+		token.Firebase.SignInProvider, _ = info["sign_in_provider"].(string)
+		token.Firebase.Tenant, _ = info["tenant"].(string)
+		token.Firebase.Identities, _ = info["identities"].(map[string]interface{})
+	}
+
+	claims := make(map[string]interface{}, len(mapClaims))
+	for k, v := range mapClaims {
+		claims[k] = v
+	}
+	for _, k := range standardClaims {
+		delete(claims, k)
+	}
+	token.Claims = claims
+	return token
+}
+
+// DeleteUser はローカル発行のユーザーがFirebaseに存在しないため何もしない
+func (i *LocalIssuer) DeleteUser(ctx context.Context, uid string) error {
+	return nil
+}
+
+func int64Claim(claims jwt.MapClaims, key string) int64 {
+	if v, ok := claims[key].(float64); ok {
+		return int64(v)
+	}
+	return 0
+}
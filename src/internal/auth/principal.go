@@ -0,0 +1,147 @@
+// Seicheese-Backend/src/internal/auth/principal.go
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"seicheese/internal/appversion"
+
+	firebaseauth "firebase.google.com/go/v4/auth"
+)
+
+// Role は利用者の役割（IDトークンのroleクレーム）
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
+// Valid は定義済みの役割かどうか
+func (r Role) Valid() bool {
+	return r == RoleUser || r == RoleAdmin
+}
+
+// Principal は検証済みのIDトークンから得た利用者
+type Principal struct {
+	// Firebase UID
+	UID string
+	// roleクレーム（ない場合はRoleUser）
+	Role Role
+	// app_versionクレーム（ない場合は空）
+	AppVersion string
+	// ログイン方法（password、google.comなど）
+	SignInProvider string
+	// Identity Platformのテナント（テナントを使わない場合は空）
+	Tenant string
+	// トークンの有効期限
+	ExpiresAt time.Time
+}
+
+// IsAdmin は管理者かどうか
+func (p *Principal) IsAdmin() bool {
+	return p.Role == RoleAdmin
+}
+
+// Authenticator はIDトークンの署名を検証し、発行者・有効期限・クレームを確認してPrincipalを返す
+type Authenticator struct {
+	Verifier TokenVerifier
+	// 期待する発行者（iss）
+	Issuer string
+	// 期待する対象（aud、空の場合は確認しない）
+	Audience string
+	// 期待するテナント（空の場合はテナントに属さないユーザーのトークンだけを受け付ける）
+	Tenant string
+	// 現在時刻（nilの場合はtime.Now、テスト用）
+	Now func() time.Time
+}
+
+// NewAuthenticator は設定のプロジェクト（とテナント）が発行したトークンだけを受け付けるAuthenticatorを作成
+func NewAuthenticator(verifier TokenVerifier, config *Config) *Authenticator {
+	return &Authenticator{
+		Verifier: verifier,
+		Issuer:   config.ExpectedIssuer(),
+		Audience: config.ExpectedAudience(),
+		Tenant:   config.TenantID,
+	}
+}
+
+// Authenticate はIDトークンを検証してPrincipalを返す
+func (a *Authenticator) Authenticate(ctx context.Context, idToken string) (*Principal, error) {
+	token, err := a.Verifier.VerifyIDToken(ctx, idToken)
+	if err != nil {
+		return nil, err
+	}
+	return a.Check(token)
+}
+
+// Check は署名を検証済みのトークンの発行者・有効期限・クレームを確認する
+func (a *Authenticator) Check(token *firebaseauth.Token) (*Principal, error) {
+	if token == nil {
+		return nil, errors.New("token is nil")
+	}
+
+	now := time.Now()
+	if a.Now != nil {
+		now = a.Now()
+	}
+
+	// 有効期限と発行時刻
+	expiresAt := time.Unix(token.Expires, 0)
+	if expiresAt.Before(now) {
+		return nil, fmt.Errorf("token has expired at %v", expiresAt)
+	}
+	if issuedAt := time.Unix(token.IssuedAt, 0); issuedAt.After(now) {
+		return nil, fmt.Errorf("token was issued in the future at %v", issuedAt)
+	}
+
+	// 発行者・対象・テナント
+	if token.Issuer != a.Issuer {
+		return nil, fmt.Errorf("invalid token issuer: expected %s, got %s", a.Issuer, token.Issuer)
+	}
+	if a.Audience != "" && token.Audience != a.Audience {
+		return nil, fmt.Errorf("invalid token audience: expected %s, got %s", a.Audience, token.Audience)
+	}
+	if token.Firebase.Tenant != a.Tenant {
+		return nil, fmt.Errorf("invalid token tenant: expected %q, got %q", a.Tenant, token.Firebase.Tenant)
+	}
+
+	principal := &Principal{
+		UID:            token.UID,
+		Role:           RoleUser,
+		SignInProvider: token.Firebase.SignInProvider,
+		Tenant:         token.Firebase.Tenant,
+		ExpiresAt:      expiresAt,
+	}
+
+	// カスタムクレーム
+	if claims := token.Claims; claims != nil {
+		if uid, ok := claims["user_id"].(string); !ok || uid == "" {
+			return nil, errors.New("missing or invalid user_id claim")
+		}
+
+		// サポート対象かどうかはリクエストごとにappversion.Policyで確認する
+		if appVersion, ok := claims["app_version"].(string); ok {
+			if _, err := appversion.Parse(appVersion); err != nil {
+				return nil, fmt.Errorf("invalid app_version claim: %w", err)
+			}
+			principal.AppVersion = appVersion
+		}
+
+		if role, ok := claims["role"].(string); ok {
+			if !Role(role).Valid() {
+				return nil, fmt.Errorf("invalid role: %s", role)
+			}
+			principal.Role = Role(role)
+		}
+	}
+
+	if principal.UID == "" {
+		return nil, errors.New("token has no uid")
+	}
+	return principal, nil
+}
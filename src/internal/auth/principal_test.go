@@ -0,0 +1,115 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"seicheese/internal/auth"
+
+	firebaseauth "firebase.google.com/go/v4/auth"
+)
+
+const testIssuer = "https://securetoken.google.com/seicheese-test"
+
+var testNow = time.Date(2024, 11, 1, 9, 0, 0, 0, time.UTC)
+
+// verifierFunc は関数をauth.TokenVerifierとして使う
+type verifierFunc func(ctx context.Context, idToken string) (*firebaseauth.Token, error)
+
+func (f verifierFunc) VerifyIDToken(ctx context.Context, idToken string) (*firebaseauth.Token, error) {
+	return f(ctx, idToken)
+}
+
+func newToken(claims map[string]interface{}) *firebaseauth.Token {
+	return &firebaseauth.Token{
+		Issuer:   testIssuer,
+		Audience: "seicheese-test",
+		Expires:  testNow.Add(time.Hour).Unix(),
+		IssuedAt: testNow.Add(-time.Minute).Unix(),
+		UID:      "uid-1",
+		Firebase: firebaseauth.FirebaseInfo{SignInProvider: "password"},
+		Claims:   claims,
+	}
+}
+
+func newAuthenticator(token *firebaseauth.Token) *auth.Authenticator {
+	authenticator := auth.NewAuthenticator(verifierFunc(func(ctx context.Context, idToken string) (*firebaseauth.Token, error) {
+		if idToken != "valid" {
+			return nil, errors.New("signature is invalid")
+		}
+		return token, nil
+	}), &auth.Config{Mode: auth.ModeFirebase, ProjectID: "seicheese-test"})
+	authenticator.Now = func() time.Time { return testNow }
+	return authenticator
+}
+
+func TestAuthenticate(t *testing.T) {
+	principal, err := newAuthenticator(newToken(map[string]interface{}{
+		"user_id":     "uid-1",
+		"app_version": "1.2.0",
+		"role":        "admin",
+	})).Authenticate(context.Background(), "valid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := auth.Principal{
+		UID:            "uid-1",
+		Role:           auth.RoleAdmin,
+		AppVersion:     "1.2.0",
+		SignInProvider: "password",
+		ExpiresAt:      principal.ExpiresAt,
+	}
+	if *principal != want || !principal.IsAdmin() || !principal.ExpiresAt.Equal(testNow.Add(time.Hour)) {
+		t.Errorf("principal = %+v, want %+v expiring at %v", *principal, want, testNow.Add(time.Hour))
+	}
+
+	// roleクレームがない場合は一般ユーザー
+	principal, err = newAuthenticator(newToken(map[string]interface{}{"user_id": "uid-1"})).Authenticate(context.Background(), "valid")
+	if err != nil || principal.Role != auth.RoleUser || principal.IsAdmin() {
+		t.Errorf("principal without role = %+v, %v; want user", principal, err)
+	}
+
+	if _, err := newAuthenticator(newToken(nil)).Authenticate(context.Background(), "forged"); err == nil {
+		t.Error("invalid signature: expected error")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(token *firebaseauth.Token)
+		wantErr string
+	}{
+		{"valid", func(*firebaseauth.Token) {}, ""},
+		{"expired", func(token *firebaseauth.Token) { token.Expires = testNow.Add(-time.Second).Unix() }, "expired"},
+		{"issued in the future", func(token *firebaseauth.Token) { token.IssuedAt = testNow.Add(time.Minute).Unix() }, "future"},
+		{"other project", func(token *firebaseauth.Token) {
+			token.Issuer = "https://securetoken.google.com/other-project"
+		}, "invalid token issuer"},
+		{"other audience", func(token *firebaseauth.Token) { token.Audience = "other-project" }, "invalid token audience"},
+		{"tenant user without tenant", func(token *firebaseauth.Token) { token.Firebase.Tenant = "tenant-a" }, "invalid token tenant"},
+		{"missing user_id", func(token *firebaseauth.Token) { delete(token.Claims, "user_id") }, "user_id"},
+		{"malformed app version", func(token *firebaseauth.Token) { token.Claims["app_version"] = "1.2" }, "invalid app_version claim"},
+		{"unknown role", func(token *firebaseauth.Token) { token.Claims["role"] = "owner" }, "invalid role"},
+		{"missing uid", func(token *firebaseauth.Token) { token.UID = "" }, "no uid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := newToken(map[string]interface{}{"user_id": "uid-1"})
+			tt.modify(token)
+			_, err := newAuthenticator(token).Check(token)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+
+	firebaseauth "firebase.google.com/go/v4/auth"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const tracerName = "seicheese/internal/auth"
+
+// Trace はトークンの検証ごとにOpenTelemetryのスパンを記録するProviderを返す
+// Firebaseでは公開鍵の取得（初回・更新時）もこのスパンに含まれる
+func Trace(provider Provider) Provider {
+	return &tracedProvider{Provider: provider}
+}
+
+type tracedProvider struct {
+	Provider
+}
+
+func (p *tracedProvider) VerifyIDToken(ctx context.Context, idToken string) (*firebaseauth.Token, error) {
+	return p.verify(ctx, "auth.VerifyIDToken", idToken, p.Provider.VerifyIDToken)
+}
+
+// VerifyIDTokenAndCheckRevoked は元のProviderが失効を確認できない場合は署名と有効期限だけを検証する
+func (p *tracedProvider) VerifyIDTokenAndCheckRevoked(ctx context.Context, idToken string) (*firebaseauth.Token, error) {
+	return p.verify(ctx, "auth.VerifyIDTokenAndCheckRevoked", idToken, func(ctx context.Context, idToken string) (*firebaseauth.Token, error) {
+		return verifyAndCheckRevoked(ctx, p.Provider, idToken)
+	})
+}
+
+func (p *tracedProvider) verify(ctx context.Context, name, idToken string, verify func(ctx context.Context, idToken string) (*firebaseauth.Token, error)) (*firebaseauth.Token, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, name)
+	defer span.End()
+
+	token, err := verify(ctx, idToken)
+	if err != nil {
+		// エラーにはトークンの内容が含まれる場合があるため記録しない
+		span.SetStatus(codes.Error, "invalid token")
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("auth.sign_in_provider", token.Firebase.SignInProvider))
+	return token, nil
+}
@@ -0,0 +1,45 @@
+// Seicheese-Backend/src/internal/auth/verifier.go
+
+package auth
+
+import (
+	"context"
+
+	firebaseauth "firebase.google.com/go/v4/auth"
+)
+
+// TokenVerifier はIDトークンを検証してクレームを返す
+// Firebaseの*auth.Clientはこのインターフェースをそのまま満たす
+type TokenVerifier interface {
+	VerifyIDToken(ctx context.Context, idToken string) (*firebaseauth.Token, error)
+}
+
+// RevocationChecker はトークンの失効（パスワードの変更や強制ログアウト）も確認できるTokenVerifier
+// Firebaseの*auth.Clientはこのインターフェースを満たす（確認のたびにFirebase Authからユーザー情報を取得する）
+type RevocationChecker interface {
+	VerifyIDTokenAndCheckRevoked(ctx context.Context, idToken string) (*firebaseauth.Token, error)
+}
+
+// verifyAndCheckRevoked はverifierが失効を確認できる場合は確認し、できない場合は署名と有効期限だけを検証する
+// ローカル発行のトークンは失効させられないため、LocalIssuerは後者になる
+func verifyAndCheckRevoked(ctx context.Context, verifier TokenVerifier, idToken string) (*firebaseauth.Token, error) {
+	if checker, ok := verifier.(RevocationChecker); ok {
+		return checker.VerifyIDTokenAndCheckRevoked(ctx, idToken)
+	}
+	return verifier.VerifyIDToken(ctx, idToken)
+}
+
+// UserDeleter は認証基盤側のユーザーを削除する
+type UserDeleter interface {
+	DeleteUser(ctx context.Context, uid string) error
+}
+
+// Provider はトークン検証とユーザー削除を提供する認証基盤
+type Provider interface {
+	TokenVerifier
+	UserDeleter
+}
+
+var _ Provider = (*firebaseauth.Client)(nil)
+var _ RevocationChecker = (*firebaseauth.Client)(nil)
+var _ Provider = (*LocalIssuer)(nil)
@@ -0,0 +1,53 @@
+// Package buildinfo はビルド時に埋め込んだバージョン情報を提供する
+//
+// リリースビルドでは-ldflagsで設定する
+//
+//	go build -ldflags "-X seicheese/internal/buildinfo.Version=1.2.0 -X seicheese/internal/buildinfo.Commit=$(git rev-parse HEAD)" ./cmd/app
+//
+// 設定しない場合、コミットとビルド時刻はGoが埋め込むVCS情報（gitリポジトリ内でビルドした場合のみ）から取得する
+package buildinfo
+
+import "runtime/debug"
+
+// -ldflags "-X ..." で設定する値
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info はビルド情報
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+}
+
+// Get はビルド情報を返す（不明な項目は"unknown"）
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		settings := map[string]string{}
+		for _, s := range bi.Settings {
+			settings[s.Key] = s.Value
+		}
+		if info.Commit == "" && settings["vcs.revision"] != "" {
+			info.Commit = settings["vcs.revision"]
+			if settings["vcs.modified"] == "true" {
+				info.Commit += "-dirty"
+			}
+		}
+		if info.BuildTime == "" {
+			info.BuildTime = settings["vcs.time"]
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
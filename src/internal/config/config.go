@@ -0,0 +1,339 @@
+// Package config はサーバーとサブコマンドの設定を読み込み、起動時に検証する
+//
+// 設定は次の順で上書きされる（後のものが優先）
+//  1. 既定値
+//  2. 設定ファイル（YAML、CONFIG_FILEまたは-configで指定した場合のみ）
+//  3. 環境変数（空文字の場合は未設定として扱う）
+//
+// 各パッケージは環境変数を直接読まず、ここで作成した設定を受け取る
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"seicheese/internal/appversion"
+	"seicheese/internal/auth"
+	"seicheese/internal/infrastructure/database"
+	"seicheese/internal/infrastructure/storage"
+	"seicheese/internal/logging"
+	"seicheese/internal/ratelimit"
+	"seicheese/internal/security"
+	"seicheese/internal/tracing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvFile は設定ファイルのパスを指定する環境変数
+const EnvFile = "CONFIG_FILE"
+
+// Config はアプリケーション全体の設定
+type Config struct {
+	// HTTPサーバーのポート（PORT）
+	Port string `yaml:"port"`
+	// 起動時に未適用のマイグレーションを適用するか（AUTO_MIGRATE）
+	AutoMigrate bool `yaml:"auto_migrate"`
+	// 停止時に処理中のリクエストの完了を待つ時間（SHUTDOWN_TIMEOUT、例: 15s）
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// ログレベル（LOG_LEVEL、debug・info・warn・error）
+	LogLevel string `yaml:"log_level"`
+
+	Database database.DBConfig `yaml:"database"`
+	Auth     auth.Config       `yaml:"auth"`
+	Storage  storage.Config    `yaml:"storage"`
+	Tracing  tracing.Config    `yaml:"tracing"`
+	// リクエスト数の制限（RATE_LIMIT_ENABLED、ルートごとの上限は設定ファイルのみ）
+	RateLimit ratelimit.Config `yaml:"rate_limit"`
+	// クロスオリジンリクエストの許可（CORS_ALLOW_ORIGINS・CORS_ALLOW_METHODSはカンマ区切り）
+	CORS security.CORSConfig `yaml:"cors"`
+	// セキュリティヘッダー・リクエストボディの上限・信頼するプロキシ（TRUSTED_PROXIESはカンマ区切り）
+	Security security.Config `yaml:"security"`
+	// アプリのバージョンの方針（APP_*、プラットフォームごとの最低・推奨バージョンは設定ファイルのみ）
+	AppVersion appversion.Config `yaml:"app_version"`
+
+	// Firebase Admin SDKの認証情報ファイルのパス（FIREBASE_SDK_PATH）
+	FirebaseSDKPath string `yaml:"firebase_sdk_path"`
+	// Google Maps Geocoding APIのキー（GOOGLE_MAPS_API_KEY）
+	GoogleMapsAPIKey string `yaml:"google_maps_api_key"`
+	// 表示名・自己紹介に使えない語を追加するファイルのパス（NG_WORDS_FILE、1行1語）
+	NGWordsFile string `yaml:"ng_words_file"`
+}
+
+// Default は既定値の設定を返す
+func Default() *Config {
+	return &Config{
+		Port:            "1300",
+		ShutdownTimeout: 10 * time.Second,
+		LogLevel:        "info",
+		Auth: auth.Config{
+			Mode:           auth.ModeFirebase,
+			LocalAlgorithm: jwt.SigningMethodHS256.Alg(),
+			TokenCacheSize: 10000,
+		},
+		Storage: storage.Config{
+			LocalDir:      "uploads",
+			PublicBaseURL: "/uploads",
+		},
+		Tracing: tracing.Config{
+			Exporter:    tracing.ExporterNone,
+			SampleRatio: 1,
+		},
+		// Google Maps APIを呼び出す登録系のルートを制限する
+		RateLimit: ratelimit.Config{
+			Enabled: true,
+			Routes: map[string]ratelimit.Limit{
+				"POST /api/seichi/register":   {Requests: 10, Per: time.Minute, Burst: 5},
+				"POST /api/places":            {Requests: 10, Per: time.Minute, Burst: 5},
+				"POST /api/contents/register": {Requests: 20, Per: time.Minute, Burst: 5},
+				"POST /api/checkins":          {Requests: 30, Per: time.Minute, Burst: 10},
+			},
+		},
+		CORS: security.CORSConfig{
+			AllowOrigins: []string{"*"},
+			AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+			MaxAge:       10 * time.Minute,
+		},
+		Security: security.Config{
+			HSTSMaxAge:      365 * 24 * time.Hour,
+			BodyLimit:       "1M",
+			UploadBodyLimit: "6M",
+		},
+		AppVersion: appversion.Config{
+			MinSupported: "0.1.0",
+		},
+	}
+}
+
+// Load は既定値に設定ファイル（pathが空の場合は読まない）と環境変数を重ねた設定を返す
+// 検証は行わないため、用途に応じてValidateなどを呼び出すこと
+func Load(path string) (*Config, error) {
+	config := Default()
+	if path != "" {
+		if err := config.loadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to load config file %s: %w", path, err)
+		}
+	}
+	if err := config.loadEnv(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// 未知の項目はタイプミスの可能性が高いためエラーにする
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	// 空のファイルは既定値のままとする
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+func (c *Config) loadEnv() error {
+	vars := map[string]*string{
+		"PORT":                        &c.Port,
+		"LOG_LEVEL":                   &c.LogLevel,
+		"DB_USER":                     &c.Database.User,
+		"DB_PASS":                     &c.Database.Password,
+		"DB_HOST":                     &c.Database.Host,
+		"DB_PORT":                     &c.Database.Port,
+		"DB_NAME":                     &c.Database.Name,
+		"AUTH_MODE":                   &c.Auth.Mode,
+		"FIREBASE_PROJECT_ID":         &c.Auth.ProjectID,
+		"LOCAL_AUTH_ALGORITHM":        &c.Auth.LocalAlgorithm,
+		"LOCAL_AUTH_SECRET":           &c.Auth.LocalSecret,
+		"LOCAL_AUTH_PRIVATE_KEY_PATH": &c.Auth.LocalPrivateKeyPath,
+		"FIREBASE_AUTH_EMULATOR_HOST": &c.Auth.EmulatorHost,
+		"FIREBASE_TENANT_ID":          &c.Auth.TenantID,
+		"AUTH_ISSUER":                 &c.Auth.Issuer,
+		"AUTH_AUDIENCE":               &c.Auth.Audience,
+		"STORAGE_BUCKET":              &c.Storage.Bucket,
+		"STORAGE_LOCAL_DIR":           &c.Storage.LocalDir,
+		"STORAGE_PUBLIC_BASE_URL":     &c.Storage.PublicBaseURL,
+		"FIREBASE_SDK_PATH":           &c.FirebaseSDKPath,
+		"GOOGLE_MAPS_API_KEY":         &c.GoogleMapsAPIKey,
+		"NG_WORDS_FILE":               &c.NGWordsFile,
+		"TRACING_EXPORTER":            &c.Tracing.Exporter,
+		"TRACING_OTLP_ENDPOINT":       &c.Tracing.OTLPEndpoint,
+		"BODY_LIMIT":                  &c.Security.BodyLimit,
+		"UPLOAD_BODY_LIMIT":           &c.Security.UploadBodyLimit,
+		"APP_MIN_SUPPORTED_VERSION":   &c.AppVersion.MinSupported,
+		"APP_RECOMMENDED_VERSION":     &c.AppVersion.Recommended,
+		"APP_STORE_URL_IOS":           &c.AppVersion.IOS.StoreURL,
+		"APP_STORE_URL_ANDROID":       &c.AppVersion.Android.StoreURL,
+	}
+	for name, field := range vars {
+		if value := os.Getenv(name); value != "" {
+			*field = value
+		}
+	}
+
+	bools := map[string]*bool{
+		"AUTO_MIGRATE":                &c.AutoMigrate,
+		"AUTH_ALLOW_EMULATOR":         &c.Auth.AllowEmulator,
+		"CORS_ALLOW_CREDENTIALS":      &c.CORS.AllowCredentials,
+		"RATE_LIMIT_ENABLED":          &c.RateLimit.Enabled,
+		"TRACING_OTLP_INSECURE":       &c.Tracing.OTLPInsecure,
+		"APP_ALLOW_PRERELEASE":        &c.AppVersion.AllowPrerelease,
+		"APP_VERSION_HEADER_REQUIRED": &c.AppVersion.RequireHeader,
+	}
+	for name, field := range bools {
+		if value := os.Getenv(name); value != "" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%s must be a boolean: %q", name, value)
+			}
+			*field = b
+		}
+	}
+
+	lists := map[string]*[]string{
+		"CORS_ALLOW_ORIGINS": &c.CORS.AllowOrigins,
+		"CORS_ALLOW_METHODS": &c.CORS.AllowMethods,
+		"TRUSTED_PROXIES":    &c.Security.TrustedProxies,
+	}
+	for name, field := range lists {
+		if value := os.Getenv(name); value != "" {
+			*field = splitList(value)
+		}
+	}
+
+	if value := os.Getenv("TRACING_SAMPLE_RATIO"); value != "" {
+		ratio, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("TRACING_SAMPLE_RATIO must be a number: %q", value)
+		}
+		c.Tracing.SampleRatio = ratio
+	}
+
+	ints := map[string]*int{
+		"AUTH_TOKEN_CACHE_SIZE": &c.Auth.TokenCacheSize,
+	}
+	for name, field := range ints {
+		if value := os.Getenv(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%s must be an integer: %q", name, value)
+			}
+			*field = n
+		}
+	}
+
+	durations := map[string]*time.Duration{
+		"SHUTDOWN_TIMEOUT":               &c.ShutdownTimeout,
+		"CORS_MAX_AGE":                   &c.CORS.MaxAge,
+		"HSTS_MAX_AGE":                   &c.Security.HSTSMaxAge,
+		"AUTH_REVOCATION_CHECK_INTERVAL": &c.Auth.RevocationCheckInterval,
+	}
+	for name, field := range durations {
+		if value := os.Getenv(name); value != "" {
+			duration, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("%s must be a duration such as 10s: %q", name, value)
+			}
+			*field = duration
+		}
+	}
+	return nil
+}
+
+// splitList はカンマ区切りの値を分割する（前後の空白と空の要素は除く）
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// UsesFirebase はFirebaseアプリの初期化が必要かどうか（Firebase認証またはFirebase Storageを使う場合）
+func (c *Config) UsesFirebase() bool {
+	return c.Auth.UsesFirebase() || c.Storage.Bucket != ""
+}
+
+// Validate はサーバーの起動に必要な設定をすべて検証し、不足・誤りをまとめたエラーを返す
+func (c *Config) Validate() error {
+	errs := []error{c.validatePort(), c.validateNGWordsFile(), c.Database.Validate(), c.Auth.Validate(), c.Tracing.Validate(), c.RateLimit.Validate(), c.CORS.Validate(), c.Security.Validate(), c.AppVersion.Validate()}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("SHUTDOWN_TIMEOUT must be positive: %s", c.ShutdownTimeout))
+	}
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL: %w", err))
+	}
+	if c.needsFirebaseCredentials() {
+		errs = append(errs, c.validateFirebaseSDKPath())
+	}
+	return errors.Join(errs...)
+}
+
+// CheckFirebase はFirebaseを使う場合に必要な設定を確認する（準備状態の確認用）
+func (c *Config) CheckFirebase(ctx context.Context) error {
+	if !c.UsesFirebase() {
+		return nil
+	}
+	if c.Auth.UsesFirebase() && c.Auth.ProjectID == "" {
+		return errors.New("FIREBASE_PROJECT_ID is not set")
+	}
+	if !c.needsFirebaseCredentials() {
+		return nil
+	}
+	return c.validateFirebaseSDKPath()
+}
+
+// needsFirebaseCredentials はサービスアカウントの認証情報が必要かどうか
+// Firebase Auth Emulatorだけを使う場合（Firebase Storageを使わない場合）は不要
+func (c *Config) needsFirebaseCredentials() bool {
+	if c.Storage.Bucket != "" {
+		return true
+	}
+	return c.Auth.UsesFirebase() && !c.Auth.UsesEmulator()
+}
+
+func (c *Config) validatePort() error {
+	if port, err := strconv.Atoi(c.Port); err != nil || port <= 0 || port > 65535 {
+		return fmt.Errorf("PORT must be a port number: %q", c.Port)
+	}
+	return nil
+}
+
+func (c *Config) validateNGWordsFile() error {
+	if c.NGWordsFile == "" {
+		return nil
+	}
+	file, err := os.Open(c.NGWordsFile)
+	if err != nil {
+		return fmt.Errorf("NG_WORDS_FILE is not readable: %w", err)
+	}
+	defer file.Close()
+	if info, err := file.Stat(); err != nil || info.IsDir() {
+		return fmt.Errorf("NG_WORDS_FILE is not a readable file: %s", c.NGWordsFile)
+	}
+	return nil
+}
+
+func (c *Config) validateFirebaseSDKPath() error {
+	if c.FirebaseSDKPath == "" {
+		return errors.New("FIREBASE_SDK_PATH is required when using Firebase Authentication or Firebase Storage")
+	}
+	if _, err := os.Stat(c.FirebaseSDKPath); err != nil {
+		return fmt.Errorf("FIREBASE_SDK_PATH is not readable: %w", err)
+	}
+	return nil
+}
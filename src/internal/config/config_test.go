@@ -0,0 +1,321 @@
+package config_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"seicheese/internal/auth"
+	"seicheese/internal/config"
+	"seicheese/internal/ratelimit"
+	"seicheese/internal/tracing"
+)
+
+// 実行環境の環境変数の影響を受けないよう、読み込む環境変数をすべて未設定にする
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{
+		"PORT", "AUTO_MIGRATE", "SHUTDOWN_TIMEOUT", "LOG_LEVEL",
+		"DB_USER", "DB_PASS", "DB_HOST", "DB_PORT", "DB_NAME",
+		"AUTH_MODE", "FIREBASE_PROJECT_ID", "LOCAL_AUTH_ALGORITHM", "LOCAL_AUTH_SECRET", "LOCAL_AUTH_PRIVATE_KEY_PATH",
+		"AUTH_TOKEN_CACHE_SIZE", "AUTH_REVOCATION_CHECK_INTERVAL",
+		"FIREBASE_AUTH_EMULATOR_HOST", "AUTH_ALLOW_EMULATOR", "FIREBASE_TENANT_ID", "AUTH_ISSUER", "AUTH_AUDIENCE",
+		"STORAGE_BUCKET", "STORAGE_LOCAL_DIR", "STORAGE_PUBLIC_BASE_URL",
+		"FIREBASE_SDK_PATH", "GOOGLE_MAPS_API_KEY", "NG_WORDS_FILE",
+		"TRACING_EXPORTER", "TRACING_OTLP_ENDPOINT", "TRACING_OTLP_INSECURE", "TRACING_SAMPLE_RATIO",
+		"RATE_LIMIT_ENABLED",
+		"CORS_ALLOW_ORIGINS", "CORS_ALLOW_METHODS", "CORS_ALLOW_CREDENTIALS", "CORS_MAX_AGE",
+		"HSTS_MAX_AGE", "BODY_LIMIT", "UPLOAD_BODY_LIMIT", "TRUSTED_PROXIES",
+		"APP_MIN_SUPPORTED_VERSION", "APP_RECOMMENDED_VERSION", "APP_ALLOW_PRERELEASE", "APP_VERSION_HEADER_REQUIRED",
+		"APP_STORE_URL_IOS", "APP_STORE_URL_ANDROID",
+	} {
+		t.Setenv(name, "")
+	}
+}
+
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != "1300" || cfg.ShutdownTimeout != 10*time.Second || cfg.Auth.Mode != auth.ModeFirebase || cfg.Auth.LocalAlgorithm != "HS256" || cfg.Storage.LocalDir != "uploads" || cfg.Tracing.Exporter != tracing.ExporterNone || cfg.Tracing.SampleRatio != 1 {
+		t.Errorf("defaults = %+v", cfg)
+	}
+	if len(cfg.CORS.AllowOrigins) != 1 || cfg.CORS.AllowOrigins[0] != "*" || !slices.Contains(cfg.CORS.AllowMethods, http.MethodPut) || !slices.Contains(cfg.CORS.AllowMethods, http.MethodDelete) {
+		t.Errorf("CORS defaults = %+v, want all origins and PUT/DELETE allowed", cfg.CORS)
+	}
+	if cfg.Security.BodyLimit != "1M" || cfg.Security.UploadBodyLimit != "6M" || cfg.Security.HSTSMaxAge <= 0 {
+		t.Errorf("Security defaults = %+v", cfg.Security)
+	}
+	if cfg.Auth.TokenCacheSize != 10000 || cfg.Auth.RevocationCheckInterval != 0 {
+		t.Errorf("Auth defaults = %+v, want token cache without revocation checks", cfg.Auth)
+	}
+	if cfg.AppVersion.MinSupported != "0.1.0" || cfg.AppVersion.Recommended != "" || cfg.AppVersion.RequireHeader {
+		t.Errorf("AppVersion defaults = %+v, want min 0.1.0 without recommendation", cfg.AppVersion)
+	}
+}
+
+func TestLoadFileAndEnv(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", `
+port: "8080"
+shutdown_timeout: 30s
+database:
+  user: app
+  host: db
+  port: "3306"
+  name: SeiCheese
+auth:
+  firebase_project_id: from-file
+google_maps_api_key: file-key
+tracing:
+  exporter: otlp
+  sample_ratio: 0.25
+rate_limit:
+  routes:
+    "POST /api/checkins": { requests: 5, per: 1m }
+app_version:
+  min_supported: 1.0.0
+  recommended: 1.2.0
+  android:
+    min_supported: 1.0.5
+`)
+	t.Setenv("FIREBASE_PROJECT_ID", "from-env")
+	t.Setenv("AUTO_MIGRATE", "true")
+	t.Setenv("TRACING_OTLP_INSECURE", "true")
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	t.Setenv("CORS_ALLOW_ORIGINS", "https://admin.seicheese.jp, http://localhost:3000")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	t.Setenv("CORS_MAX_AGE", "1h")
+	t.Setenv("UPLOAD_BODY_LIMIT", "10M")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 35.191.0.0/16")
+	t.Setenv("APP_MIN_SUPPORTED_VERSION", "1.1.0")
+	t.Setenv("AUTH_TOKEN_CACHE_SIZE", "0")
+	t.Setenv("AUTH_REVOCATION_CHECK_INTERVAL", "5m")
+	t.Setenv("FIREBASE_AUTH_EMULATOR_HOST", "localhost:9099")
+	t.Setenv("AUTH_ALLOW_EMULATOR", "true")
+	t.Setenv("FIREBASE_TENANT_ID", "tenant-a")
+	t.Setenv("APP_VERSION_HEADER_REQUIRED", "true")
+	t.Setenv("APP_STORE_URL_IOS", "https://apps.apple.com/jp/app/id0000000000")
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != "8080" || cfg.ShutdownTimeout != 30*time.Second || cfg.Database.User != "app" || cfg.GoogleMapsAPIKey != "file-key" {
+		t.Errorf("values from file were not loaded: %+v", cfg)
+	}
+	if cfg.Auth.ProjectID != "from-env" {
+		t.Errorf("ProjectID = %q, want environment variable to take precedence", cfg.Auth.ProjectID)
+	}
+	if !cfg.AutoMigrate {
+		t.Error("AutoMigrate = false, want true from AUTO_MIGRATE")
+	}
+	if cfg.Tracing.Exporter != tracing.ExporterOTLP || cfg.Tracing.SampleRatio != 0.25 || !cfg.Tracing.OTLPInsecure {
+		t.Errorf("Tracing = %+v, want otlp from file and insecure from TRACING_OTLP_INSECURE", cfg.Tracing)
+	}
+	// ファイルに書いたルートだけが上書きされる
+	if cfg.RateLimit.Enabled || cfg.RateLimit.Routes["POST /api/checkins"].Requests != 5 || cfg.RateLimit.Routes["POST /api/seichi/register"].Requests != 10 {
+		t.Errorf("RateLimit = %+v, want checkins from file, defaults for others and disabled by RATE_LIMIT_ENABLED", cfg.RateLimit)
+	}
+	if !slices.Equal(cfg.CORS.AllowOrigins, []string{"https://admin.seicheese.jp", "http://localhost:3000"}) || !cfg.CORS.AllowCredentials || cfg.CORS.MaxAge != time.Hour {
+		t.Errorf("CORS = %+v, want values from CORS_* environment variables", cfg.CORS)
+	}
+	if cfg.Security.UploadBodyLimit != "10M" || cfg.Security.BodyLimit != "1M" || !slices.Equal(cfg.Security.TrustedProxies, []string{"10.0.0.0/8", "35.191.0.0/16"}) {
+		t.Errorf("Security = %+v, want UPLOAD_BODY_LIMIT and TRUSTED_PROXIES from environment and default BODY_LIMIT", cfg.Security)
+	}
+	if v := cfg.AppVersion; v.MinSupported != "1.1.0" || v.Recommended != "1.2.0" || v.Android.MinSupported != "1.0.5" || !v.RequireHeader || v.IOS.StoreURL == "" {
+		t.Errorf("AppVersion = %+v, want values from file overridden by APP_* environment variables", v)
+	}
+	// 0も設定値として扱う
+	if cfg.Auth.TokenCacheSize != 0 || cfg.Auth.RevocationCheckInterval != 5*time.Minute {
+		t.Errorf("Auth = %+v, want values from AUTH_TOKEN_CACHE_SIZE and AUTH_REVOCATION_CHECK_INTERVAL", cfg.Auth)
+	}
+	if !cfg.Auth.UsesEmulator() || !cfg.Auth.AllowEmulator || cfg.Auth.TenantID != "tenant-a" || cfg.Auth.ExpectedIssuer() != "https://securetoken.google.com/from-env" || cfg.Auth.ExpectedAudience() != "from-env" {
+		t.Errorf("Auth = %+v, want emulator and tenant from environment and issuer and audience of the project", cfg.Auth)
+	}
+	// ファイルにない項目は既定値のまま
+	if cfg.Auth.Mode != auth.ModeFirebase || cfg.Storage.PublicBaseURL != "/uploads" {
+		t.Errorf("defaults were overwritten: %+v", cfg)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	clearEnv(t)
+
+	if _, err := config.Load(writeFile(t, "config.yaml", "databse:\n  user: app\n")); err == nil {
+		t.Error("unknown key in config file: expected error")
+	}
+	if _, err := config.Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("missing config file: expected error")
+	}
+
+	t.Setenv("AUTO_MIGRATE", "yes please")
+	if _, err := config.Load(""); err == nil || !strings.Contains(err.Error(), "AUTO_MIGRATE") {
+		t.Errorf("invalid AUTO_MIGRATE: error = %v", err)
+	}
+	t.Setenv("AUTO_MIGRATE", "")
+
+	t.Setenv("SHUTDOWN_TIMEOUT", "10")
+	if _, err := config.Load(""); err == nil || !strings.Contains(err.Error(), "SHUTDOWN_TIMEOUT") {
+		t.Errorf("invalid SHUTDOWN_TIMEOUT: error = %v", err)
+	}
+	t.Setenv("SHUTDOWN_TIMEOUT", "")
+
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "maybe")
+	if _, err := config.Load(""); err == nil || !strings.Contains(err.Error(), "CORS_ALLOW_CREDENTIALS") {
+		t.Errorf("invalid CORS_ALLOW_CREDENTIALS: error = %v", err)
+	}
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "")
+
+	t.Setenv("HSTS_MAX_AGE", "1 year")
+	if _, err := config.Load(""); err == nil || !strings.Contains(err.Error(), "HSTS_MAX_AGE") {
+		t.Errorf("invalid HSTS_MAX_AGE: error = %v", err)
+	}
+	t.Setenv("HSTS_MAX_AGE", "")
+
+	t.Setenv("TRACING_SAMPLE_RATIO", "half")
+	if _, err := config.Load(""); err == nil || !strings.Contains(err.Error(), "TRACING_SAMPLE_RATIO") {
+		t.Errorf("invalid TRACING_SAMPLE_RATIO: error = %v", err)
+	}
+	t.Setenv("TRACING_SAMPLE_RATIO", "")
+
+	t.Setenv("AUTH_TOKEN_CACHE_SIZE", "many")
+	if _, err := config.Load(""); err == nil || !strings.Contains(err.Error(), "AUTH_TOKEN_CACHE_SIZE") {
+		t.Errorf("invalid AUTH_TOKEN_CACHE_SIZE: error = %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	sdkPath := writeFile(t, "sdk.json", "{}")
+
+	valid := func() *config.Config {
+		cfg := config.Default()
+		cfg.Database.User = "root"
+		cfg.Database.Host = "db"
+		cfg.Database.Port = "3306"
+		cfg.Database.Name = "SeiCheese"
+		cfg.Auth.ProjectID = "seicheese"
+		cfg.FirebaseSDKPath = sdkPath
+		return cfg
+	}
+
+	tests := []struct {
+		name   string
+		modify func(cfg *config.Config)
+		want   []string
+	}{
+		{"valid", func(cfg *config.Config) {}, nil},
+		{"missing database settings", func(cfg *config.Config) {
+			cfg.Database.Host = ""
+			cfg.Database.Name = ""
+		}, []string{"DB_HOST is required", "DB_NAME is required"}},
+		{"missing firebase project", func(cfg *config.Config) { cfg.Auth.ProjectID = "" }, []string{"FIREBASE_PROJECT_ID is required"}},
+		{"missing firebase credentials", func(cfg *config.Config) { cfg.FirebaseSDKPath = "" }, []string{"FIREBASE_SDK_PATH is required"}},
+		{"unreadable firebase credentials", func(cfg *config.Config) {
+			cfg.FirebaseSDKPath = filepath.Join(t.TempDir(), "missing.json")
+		}, []string{"FIREBASE_SDK_PATH is not readable"}},
+		{"ng words file", func(cfg *config.Config) { cfg.NGWordsFile = writeFile(t, "ngwords.txt", "ばか\n") }, nil},
+		{"missing ng words file", func(cfg *config.Config) {
+			cfg.NGWordsFile = filepath.Join(t.TempDir(), "missing.txt")
+		}, []string{"NG_WORDS_FILE is not readable"}},
+		{"ng words directory", func(cfg *config.Config) { cfg.NGWordsFile = t.TempDir() }, []string{"NG_WORDS_FILE is not a readable file"}},
+		{"invalid port", func(cfg *config.Config) { cfg.Port = "http" }, []string{"PORT must be a port number"}},
+		{"zero shutdown timeout", func(cfg *config.Config) { cfg.ShutdownTimeout = 0 }, []string{"SHUTDOWN_TIMEOUT must be positive"}},
+		{"unknown log level", func(cfg *config.Config) { cfg.LogLevel = "verbose" }, []string{"LOG_LEVEL"}},
+		{"invalid rate limit route", func(cfg *config.Config) {
+			cfg.RateLimit.Routes["/api/checkins"] = ratelimit.Limit{Requests: 1, Per: time.Minute}
+		}, []string{"rate_limit route"}},
+		{"credentials with any origin", func(cfg *config.Config) { cfg.CORS.AllowCredentials = true }, []string{"CORS_ALLOW_CREDENTIALS"}},
+		{"negative token cache size", func(cfg *config.Config) { cfg.Auth.TokenCacheSize = -1 }, []string{"AUTH_TOKEN_CACHE_SIZE must not be negative"}},
+		{"revocation check interval with cache", func(cfg *config.Config) { cfg.Auth.RevocationCheckInterval = 5 * time.Minute }, nil},
+		{"revocation check interval without cache", func(cfg *config.Config) {
+			cfg.Auth.TokenCacheSize = 0
+			cfg.Auth.RevocationCheckInterval = 5 * time.Minute
+		}, []string{"AUTH_REVOCATION_CHECK_INTERVAL requires AUTH_TOKEN_CACHE_SIZE"}},
+		{"recommended older than min version", func(cfg *config.Config) { cfg.AppVersion.Recommended = "0.0.1" }, []string{"older than min supported"}},
+		{"invalid body limit", func(cfg *config.Config) { cfg.Security.BodyLimit = "huge" }, []string{"BODY_LIMIT must be a size"}},
+		{"unknown tracing exporter", func(cfg *config.Config) { cfg.Tracing.Exporter = "zipkin" }, []string{"TRACING_EXPORTER"}},
+		{"local mode without secret", func(cfg *config.Config) {
+			cfg.Auth.Mode = auth.ModeLocal
+			cfg.FirebaseSDKPath = ""
+		}, []string{"LOCAL_AUTH_SECRET is required"}},
+		{"local mode with local storage", func(cfg *config.Config) {
+			cfg.Auth.Mode = auth.ModeLocal
+			cfg.Auth.LocalSecret = "secret"
+			cfg.FirebaseSDKPath = ""
+		}, nil},
+		{"firebase storage in local mode", func(cfg *config.Config) {
+			cfg.Auth.Mode = auth.ModeLocal
+			cfg.Auth.LocalSecret = "secret"
+			cfg.Storage.Bucket = "seicheese.appspot.com"
+			cfg.FirebaseSDKPath = ""
+		}, []string{"FIREBASE_SDK_PATH is required"}},
+		{"emulator without credentials", func(cfg *config.Config) {
+			cfg.Auth.EmulatorHost = "localhost:9099"
+			cfg.Auth.AllowEmulator = true
+			cfg.FirebaseSDKPath = ""
+		}, nil},
+		{"emulator host alone", func(cfg *config.Config) {
+			cfg.Auth.EmulatorHost = "localhost:9099"
+		}, []string{"AUTH_ALLOW_EMULATOR is not true"}},
+		{"emulator with firebase storage", func(cfg *config.Config) {
+			cfg.Auth.EmulatorHost = "localhost:9099"
+			cfg.Auth.AllowEmulator = true
+			cfg.Storage.Bucket = "seicheese.appspot.com"
+			cfg.FirebaseSDKPath = ""
+		}, []string{"FIREBASE_SDK_PATH is required"}},
+		{"emulator in local mode", func(cfg *config.Config) {
+			cfg.Auth.Mode = auth.ModeLocal
+			cfg.Auth.LocalSecret = "secret"
+			cfg.Auth.EmulatorHost = "localhost:9099"
+			cfg.Auth.AllowEmulator = true
+		}, []string{"FIREBASE_AUTH_EMULATOR_HOST requires AUTH_MODE=firebase"}},
+		{"unknown auth mode", func(cfg *config.Config) { cfg.Auth.Mode = "anonymous" }, []string{"unknown AUTH_MODE"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() = nil, want errors containing %q", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExampleConfig(t *testing.T) {
+	clearEnv(t)
+
+	if _, err := config.Load(filepath.Join("..", "..", "configs", "config.example.yaml")); err != nil {
+		t.Errorf("configs/config.example.yaml: %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"context"
+
+	"seicheese/internal/apperror"
+
+	"github.com/labstack/echo/v4"
+)
+
+// contextValidator はリクエストのContextを使って検証できるValidator
+type contextValidator interface {
+	ValidateContext(ctx context.Context, i interface{}) error
+}
+
+// リクエストをバインドし、validateタグで検証する
+// 副作用のある処理（外部APIの呼び出しやDBへの書き込み）の前に呼ぶこと
+func bindAndValidate(c echo.Context, req interface{}) error {
+	if err := c.Bind(req); err != nil {
+		return apperror.ErrInvalidRequest.Wrap(err)
+	}
+	if v, ok := c.Echo().Validator.(contextValidator); ok {
+		return v.ValidateContext(c.Request().Context(), req)
+	}
+	return c.Validate(req)
+}
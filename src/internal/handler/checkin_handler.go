@@ -1,38 +1,34 @@
 package handler
 
 import (
-	"database/sql"
+	"fmt"
 	"net/http"
 	"time"
 
+	"seicheese/internal/apperror"
+	"seicheese/internal/i18n"
+	"seicheese/internal/metrics"
+	"seicheese/internal/repository"
 	"seicheese/models"
 
 	"github.com/labstack/echo/v4"
-	"github.com/volatiletech/sqlboiler/v4/boil"
-	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 )
 
 type CheckinHandler struct {
-	DB *sql.DB
+	Checkins repository.CheckinRepository
 }
 
 func (h *CheckinHandler) GetUserCheckins(c echo.Context) error {
 	ctx := c.Request().Context()
-	uid := c.Get("uid").(string)
 
-	user, err := models.Users(
-		models.UserWhere.FirebaseID.EQ(uid),
-	).One(ctx, h.DB)
+	user, err := currentUser(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "ユーザー情報の取得に失敗")
+		return err
 	}
 
-	checkins, err := models.CheckinLogs(
-		qm.Where("user_id = ?", user.UserID),
-		qm.OrderBy("created_at DESC"),
-	).All(ctx, h.DB)
+	checkins, err := h.Checkins.ListByUser(ctx, user.UserID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "チェックイン履歴の取得に失敗")
+		return apperror.ErrInternal.Wrap(fmt.Errorf("fetch checkin logs: %w", err))
 	}
 
 	return c.JSON(http.StatusOK, checkins)
@@ -41,26 +37,18 @@ func (h *CheckinHandler) GetUserCheckins(c echo.Context) error {
 func (h *CheckinHandler) Checkin(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	// UIDの取得
-	uid := c.Get("uid").(string)
-	if uid == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "ユーザーIDが必要です")
-	}
-
 	// リクエストボディの解析
 	var req struct {
-		SeichiID int `json:"seichi_id"`
+		SeichiID int `json:"seichi_id" validate:"required,exists=seichi"`
 	}
-	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "不正なリクエスト形式")
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
 	}
 
 	// ユーザー情報の取得
-	user, err := models.Users(
-		models.UserWhere.FirebaseID.EQ(uid),
-	).One(ctx, h.DB)
+	user, err := currentUser(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "ユーザー情報の取得に失敗")
+		return err
 	}
 
 	// チェックインログの作成
@@ -71,12 +59,13 @@ func (h *CheckinHandler) Checkin(c echo.Context) error {
 	}
 
 	// データベースに保存
-	if err := checkinLog.Insert(ctx, h.DB, boil.Infer()); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "チェックイン処理に失敗")
+	if err := h.Checkins.Create(ctx, checkinLog); err != nil {
+		return apperror.ErrInternal.Wrap(fmt.Errorf("insert checkin log: %w", err))
 	}
+	metrics.CheckinsCreated.Inc()
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"message": "チェックイン成功",
+		"message": i18n.T(c, "CHECKIN_SUCCEEDED"),
 		"checkin": checkinLog,
 	})
 }
@@ -2,59 +2,48 @@
 package handler
 
 import (
-	"database/sql"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"seicheese/internal/apperror"
+	"seicheese/internal/i18n"
+	"seicheese/internal/repository"
 	"seicheese/models"
 
 	"github.com/labstack/echo/v4"
-	"github.com/volatiletech/sqlboiler/v4/boil"
-	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/null/v8"
 )
 
 type ContentHandler struct {
-	DB *sql.DB
+	Contents repository.ContentRepository
 }
 
 // 作品登録API
 func (h *ContentHandler) RegisterContent(c echo.Context) error {
 	var req struct {
-		Name    string `json:"content_name"`
-		GenreID int    `json:"genre_id"`
+		Name    string `json:"content_name" validate:"required,maxrunes=255"`
+		NameEn  string `json:"content_name_en" validate:"maxrunes=255"`
+		GenreID int    `json:"genre_id" validate:"required,exists=genre"`
 	}
 
-	if err := c.Bind(&req); err != nil {
-		log.Printf("Error binding request: %v", err)
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "不正なリクエスト形式です",
-		})
-	}
-
-	// デバッグログを追加
-	log.Printf("Received content name: %s", req.Name)
-	log.Printf("Received content genreId: %d", req.GenreID)
-
-	// コンテンツ名が空文字でないか確認
-	if req.Name == "" {
-		log.Printf("Content name is empty")
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "コンテンツ名が空です",
-		})
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
 	}
 
 	content := models.Content{
-		ContentName: req.Name,
-		GenreID:     req.GenreID,
+		ContentName:   req.Name,
+		ContentNameEn: null.NewString(req.NameEn, req.NameEn != ""),
+		GenreID:       req.GenreID,
 	}
 
-	if err := content.Insert(c.Request().Context(), h.DB, boil.Infer()); err != nil {
-		log.Printf("Error inserting content: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "作品の登録に失敗しました",
-		})
+	if err := h.Contents.Create(c.Request().Context(), &content); err != nil {
+		return apperror.ErrInternal.Wrap(fmt.Errorf("insert content: %w", err))
 	}
 
-	log.Printf("Content registered successfully: %v", content)
+	slog.InfoContext(c.Request().Context(), "content registered",
+		"content_id", content.ContentID,
+		"genre_id", content.GenreID,
+	)
 	return c.JSON(http.StatusCreated, content)
 }
 
@@ -65,19 +54,18 @@ func (h *ContentHandler) SearchContents(c echo.Context) error {
 		return c.JSON(http.StatusOK, []models.Content{}) // 空の配列を返す
 	}
 
-	contents, err := models.Contents(
-		qm.Where("content_name LIKE ?", "%"+query+"%"),
-	).All(c.Request().Context(), h.DB)
+	// 英語名が登録されている作品は英語名でも検索できる
+	contents, err := h.Contents.Search(c.Request().Context(), query)
 
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "検索に失敗しました",
-		})
+		return apperror.ErrInternal.Wrap(fmt.Errorf("search contents: %w", err))
 	}
 
 	if len(contents) == 0 {
 		return c.JSON(http.StatusOK, []models.Content{}) // 検索結果が0件の場合も空配列
 	}
 
+	localizeContents(i18n.FromContext(c), contents)
+
 	return c.JSON(http.StatusOK, contents)
 }
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"fmt"
+
+	"seicheese/internal/apperror"
+	"seicheese/internal/auth"
+	"seicheese/internal/middleware"
+	"seicheese/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ログイン中のユーザーを取得（LoadUserMiddlewareの適用が前提）
+func currentUser(c echo.Context) (*models.User, error) {
+	user, ok := middleware.UserFromContext(c)
+	if !ok {
+		// ルーティングの設定ミスでミドルウェアが適用されていない場合
+		return nil, apperror.ErrInternal.Wrap(fmt.Errorf("user is not loaded for %s %s", c.Request().Method, c.Path()))
+	}
+	return user, nil
+}
+
+// 認証された利用者を取得（FirebaseAuthMiddlewareの適用が前提）
+func currentPrincipal(c echo.Context) (*auth.Principal, error) {
+	principal, ok := middleware.PrincipalFromContext(c)
+	if !ok {
+		return nil, apperror.ErrInternal.Wrap(fmt.Errorf("principal is not set for %s %s", c.Request().Method, c.Path()))
+	}
+	return principal, nil
+}
@@ -1,24 +1,28 @@
 package handler
 
 import (
-	"database/sql"
+	"fmt"
 	"net/http"
-	"seicheese/models"
+	"seicheese/internal/apperror"
+	"seicheese/internal/i18n"
+	"seicheese/internal/repository"
 
 	"github.com/labstack/echo/v4"
 )
 
 type GenreHandler struct {
-	DB *sql.DB
+	Genres repository.GenreRepository
 }
 
 // ジャンル一覧取得API
 func (h *GenreHandler) GetGenres(c echo.Context) error {
-	genres, err := models.Genres().All(c.Request().Context(), h.DB)
+	genres, err := h.Genres.List(c.Request().Context())
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "ジャンルの取得に失敗しました")
+		return apperror.ErrInternal.Wrap(fmt.Errorf("fetch genres: %w", err))
 	}
 
+	localizeGenres(i18n.FromContext(c), genres)
+
 	c.Response().Header().Set("Content-Type", "application/json; charset=utf-8")
 	return c.JSON(http.StatusOK, genres)
 }
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"seicheese/internal/buildinfo"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 依存先の確認1件あたりのタイムアウト
+const readinessCheckTimeout = 2 * time.Second
+
+// ReadinessCheck はリクエストを受け付けるために必要な依存先の確認（エラーがなければ正常）
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthHandler は死活監視・準備状態・ビルド情報のAPI
+type HealthHandler struct {
+	Checks    []ReadinessCheck
+	BuildInfo buildinfo.Info
+
+	shuttingDown atomic.Bool
+}
+
+// ReadinessResponse は準備状態の確認結果（checksは確認名ごとに"ok"または"unavailable"）
+// 認証なしで公開するため、接続先などを含むエラー内容は返さずにログに出力する
+type ReadinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// MarkShuttingDown は停止処理の開始を記録する（以降の準備状態の確認は失敗する）
+func (h *HealthHandler) MarkShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// 死活監視API（プロセスが応答できれば常に成功する）
+func (h *HealthHandler) Livez(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{
+		"status": "ok",
+	})
+}
+
+// 準備状態API（依存先がすべて正常な場合のみ200、それ以外は503）
+func (h *HealthHandler) Readyz(c echo.Context) error {
+	response := ReadinessResponse{
+		Status: "ok",
+		Checks: map[string]string{},
+	}
+
+	if h.shuttingDown.Load() {
+		response.Status = "unavailable"
+		response.Checks["server"] = "shutting down"
+		return c.JSON(http.StatusServiceUnavailable, response)
+	}
+
+	for _, check := range h.Checks {
+		ctx, cancel := context.WithTimeout(c.Request().Context(), readinessCheckTimeout)
+		err := check.Check(ctx)
+		cancel()
+
+		if err != nil {
+			slog.WarnContext(c.Request().Context(), "readiness check failed", "check", check.Name, "error", err)
+			response.Status = "unavailable"
+			response.Checks[check.Name] = "unavailable"
+			continue
+		}
+		response.Checks[check.Name] = "ok"
+	}
+
+	if response.Status != "ok" {
+		return c.JSON(http.StatusServiceUnavailable, response)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// ビルド情報API
+func (h *HealthHandler) Version(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.BuildInfo)
+}
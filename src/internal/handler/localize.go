@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"seicheese/internal/i18n"
+	"seicheese/models"
+)
+
+// 翻訳がある場合は指定言語のジャンル名に置き換える
+func localizeGenres(lang i18n.Lang, genres models.GenreSlice) {
+	for _, genre := range genres {
+		genre.GenreName = localizedGenreName(lang, genre)
+	}
+}
+
+func localizedGenreName(lang i18n.Lang, genre *models.Genre) string {
+	if lang == i18n.English && genre.GenreNameEn.Valid && genre.GenreNameEn.String != "" {
+		return genre.GenreNameEn.String
+	}
+	return genre.GenreName
+}
+
+// 翻訳がある場合は指定言語の作品名に置き換える
+func localizeContents(lang i18n.Lang, contents models.ContentSlice) {
+	for _, content := range contents {
+		content.ContentName = localizedContentName(lang, content)
+	}
+}
+
+func localizedContentName(lang i18n.Lang, content *models.Content) string {
+	if lang == i18n.English && content.ContentNameEn.Valid && content.ContentNameEn.String != "" {
+		return content.ContentNameEn.String
+	}
+	return content.ContentName
+}
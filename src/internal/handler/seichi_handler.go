@@ -2,16 +2,17 @@
 package handler
 
 import (
-	"bytes"
 	"context"
-	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"net/http"
-	"os"
+	"seicheese/internal/apperror"
+	"seicheese/internal/i18n"
+	"seicheese/internal/metrics"
+	"seicheese/internal/repository"
 	"seicheese/models"
+	"seicheese/services"
 	"strconv"
 	"strings"
 	"time"
@@ -19,8 +20,6 @@ import (
 	"github.com/ericlagergren/decimal"
 	"github.com/labstack/echo/v4"
 	"github.com/volatiletech/null/v8"
-	"github.com/volatiletech/sqlboiler/v4/boil"
-	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 	"github.com/volatiletech/sqlboiler/v4/types"
 )
 
@@ -39,14 +38,8 @@ type SeichiResponse struct {
 }
 
 type SeichiHandler struct {
-	DB *sql.DB
-}
-
-// 既存の場所を検索する関数を追加
-func (h *SeichiHandler) findExistingPlace(ctx context.Context, address string) (*models.Place, error) {
-	return models.Places(
-		models.PlaceWhere.Address.EQ(address),
-	).One(ctx, h.DB)
+	Seichies repository.SeichiRepository
+	Geocoder services.Geocoder
 }
 
 // 住所文字列から都道府県から番地までを抽出する関数を追加
@@ -65,121 +58,62 @@ func extractAddress(fullAddress string) string {
 }
 
 type RegisterSeichiRequest struct {
-	Name        string  `json:"seichi_name"`
-	Description string  `json:"comment"`
-	Latitude    float64 `json:"latitude"`
-	Longitude   float64 `json:"longitude"`
-	ContentID   int     `json:"content_id"`
+	Name        string  `json:"seichi_name" validate:"required,maxrunes=255"`
+	Description string  `json:"comment" validate:"maxrunes=255"`
+	Latitude    float64 `json:"latitude" validate:"latitude,japan_lat"`
+	Longitude   float64 `json:"longitude" validate:"longitude,japan_lng"`
+	ContentID   int     `json:"content_id" validate:"required,exists=content"`
 }
 
 // 聖地登録API
 func (h *SeichiHandler) RegisterSeichi(c echo.Context) error {
-	// リクエストのデバッグ出力
-	body, _ := io.ReadAll(c.Request().Body)
-	log.Printf("Raw request body: %s", string(body))
-	c.Request().Body = io.NopCloser(bytes.NewBuffer(body))
-
+	// 住所の取得や登録の前に検証する
 	var req RegisterSeichiRequest
-	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
 	}
 
-	// 値の確認
-	log.Printf("Bound request data: %+v", req)
-
-	log.Printf("Received seichi name: %s", req.Name)
-	log.Printf("Received seichi description: %s", req.Description)
-	log.Printf("Received seichi latitude: %f", req.Latitude)
-	log.Printf("Received seichi longitude: %f", req.Longitude)
-	log.Printf("Received seichi contentID: %d", req.ContentID)
-
-	// Firebase UIDからユーザーIDを取得
-	uid := c.Get("uid").(string)
-	user, err := models.Users(
-		models.UserWhere.FirebaseID.EQ(uid),
-	).One(c.Request().Context(), h.DB)
+	// ミドルウェアで取得済みのユーザー
+	user, err := currentUser(c)
 	if err != nil {
-		log.Printf("Error fetching user: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "ユーザー情報の取得に失敗しました",
-		})
+		return err
 	}
 
-	log.Printf("Fetched user: %v", user)
-
 	// 住所情報を取得
-	addressData, err := getAddressFromCoordinates(req.Latitude, req.Longitude)
-	if err != nil {
-		log.Printf("Error getting address: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "住所の取得に失敗しました",
-		})
+	addressData, err := h.Geocoder.GetFormattedAddress(c.Request().Context(), req.Latitude, req.Longitude)
+	if errors.Is(err, services.ErrNoAddress) {
+		return apperror.ErrAddressNotFound.Wrap(err)
 	}
-
-	log.Printf("Fetched address data: %v", addressData)
-
-	// 住所データ取得後の処理
-	var place *models.Place
-	existingPlace, err := h.findExistingPlace(c.Request().Context(), extractAddress(addressData["address"]))
 	if err != nil {
-		if err != sql.ErrNoRows {
-			log.Printf("Error querying existing place: %v", err)
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "場所の検索に失敗しました",
-			})
-		}
-		// 新しい場所を登録
-		place = &models.Place{
-			Address: extractAddress(addressData["address"]),
-			ZipCode: addressData["postalCode"],
-		}
-		if err := place.Insert(c.Request().Context(), h.DB, boil.Infer()); err != nil {
-			log.Printf("Error inserting place: %v", err)
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "場所の登録に失敗しました",
-			})
-		}
-		log.Printf("Inserted new place: %v", place)
-	} else {
-		place = existingPlace
-		log.Printf("Using existing place: %v", place)
-	}
-
-	// 日本周辺の緯度経度の範囲を確認
-	if req.Latitude < 24.396308 || req.Latitude > 45.551483 {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "緯度の値が日本の範囲外です",
-		})
-	}
-	if req.Longitude < 122.93457 || req.Longitude > 153.986672 {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "経度の値が日本の範囲外です",
-		})
+		return apperror.ErrGeocodingFailed.Wrap(err)
 	}
 
 	latitudeDecimal := new(decimal.Big).SetFloat64(req.Latitude)
 	longitudeDecimal := new(decimal.Big).SetFloat64(req.Longitude)
 
-	log.Printf("Latitude decimal: %s", latitudeDecimal.String())
-	log.Printf("Longitude decimal: %s", longitudeDecimal.String())
-
-	// 聖地を登録
 	seichi := &models.Seichy{
-		UserID:     user.UserID,
+		UserID:     null.UintFrom(user.UserID),
 		SeichiName: req.Name,
 		Comment:    null.StringFrom(req.Description),
 		Latitude:   types.Decimal{Big: latitudeDecimal},
 		Longitude:  types.Decimal{Big: longitudeDecimal},
-		PlaceID:    place.PlaceID,
 		ContentID:  req.ContentID,
 	}
 
-	if err := seichi.Insert(c.Request().Context(), h.DB, boil.Infer()); err != nil {
-		log.Printf("Failed to create seichi: %v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to register seichi")
+	// 場所と聖地は同じトランザクションで登録される（聖地の登録に失敗した場合に場所だけが残らないように）
+	if err := h.Seichies.Create(c.Request().Context(), seichi, extractAddress(addressData["address"]), addressData["postalCode"]); err != nil {
+		return apperror.ErrInternal.Wrap(err)
 	}
+	metrics.SeichiesRegistered.Inc()
 
-	log.Printf("Seichi registered successfully: %v", seichi)
+	slog.InfoContext(c.Request().Context(), "seichi registered",
+		"seichi_id", seichi.SeichiID,
+		"content_id", seichi.ContentID,
+		"place_id", seichi.PlaceID,
+		"user_id", user.UserID,
+		"latitude", req.Latitude,
+		"longitude", req.Longitude,
+	)
 
 	var comment string
 	if seichi.Comment.Valid {
@@ -215,25 +149,19 @@ func (h *SeichiHandler) GetSeichies(c echo.Context) error {
 	offset := (page - 1) * limit
 
 	// 表示範囲内のデータのみを取得
-	seichies, err := models.Seichies(
-		qm.Load("Content"),
-		qm.Load("Place"),
-		qm.Limit(limit),
-		qm.Offset(offset),
-	).All(c.Request().Context(), h.DB)
+	seichies, err := h.Seichies.List(c.Request().Context(), limit, offset)
 
 	if err != nil {
-		log.Printf("Error fetching seichies: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "聖地データの取得に失敗しました",
-		})
+		return apperror.ErrInternal.Wrap(fmt.Errorf("fetch seichies: %w", err))
 	}
 
+	lang := i18n.FromContext(c)
+
 	var response []map[string]interface{}
 	for _, s := range seichies {
 		contentName := ""
 		if s.R != nil && s.R.Content != nil {
-			contentName = s.R.Content.ContentName
+			contentName = localizedContentName(lang, s.R.Content)
 		}
 
 		address := ""
@@ -267,85 +195,19 @@ func (h *SeichiHandler) GetSeichies(c echo.Context) error {
 
 // クラスタリング処理を行う関数
 func (h *SeichiHandler) getClusteredSeichies(ctx context.Context, bounds string) ([]map[string]interface{}, error) {
-	// SQLでグリッドベースのクラスタリングを実行
-	query := `
-	SELECT 
-		ROUND(AVG(CAST(latitude AS FLOAT)), 4) as lat,
-		ROUND(AVG(CAST(longitude AS FLOAT)), 4) as lng,
-		COUNT(*) as count
-	FROM seichies
-	GROUP BY 
-		FLOOR(CAST(latitude AS FLOAT) * 100),
-		FLOOR(CAST(longitude AS FLOAT) * 100)
-	HAVING count > 1
-	`
-
-	rows, err := h.DB.QueryContext(ctx, query)
+	clusters, err := h.Seichies.Clusters(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var clusters []map[string]interface{}
-	for rows.Next() {
-		var lat, lng float64
-		var count int
-		if err := rows.Scan(&lat, &lng, &count); err != nil {
-			return nil, err
-		}
-		clusters = append(clusters, map[string]interface{}{
-			"latitude":   lat,
-			"longitude":  lng,
-			"count":      count,
+	var response []map[string]interface{}
+	for _, cluster := range clusters {
+		response = append(response, map[string]interface{}{
+			"latitude":   cluster.Latitude,
+			"longitude":  cluster.Longitude,
+			"count":      cluster.Count,
 			"is_cluster": true,
 		})
 	}
-	return clusters, nil
-}
-
-func getAddressFromCoordinates(lat, lng float64) (map[string]string, error) {
-	url := fmt.Sprintf(
-		"https://maps.googleapis.com/maps/api/geocode/json?latlng=%f,%f&key=%s&language=ja",
-		lat, lng, os.Getenv("GOOGLE_MAPS_API_KEY"),
-	)
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	var result struct {
-		Results []struct {
-			FormattedAddress  string `json:"formatted_address"`
-			AddressComponents []struct {
-				Types    []string `json:"types"`
-				LongName string   `json:"long_name"`
-			} `json:"address_components"`
-		} `json:"results"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
-
-	if len(result.Results) == 0 {
-		return nil, fmt.Errorf("no results found")
-	}
-
-	// 郵便番号を探す
-	var postalCode string
-	for _, component := range result.Results[0].AddressComponents {
-		for _, type_ := range component.Types {
-			if type_ == "postal_code" {
-				postalCode = component.LongName
-				break
-			}
-		}
-	}
-
-	return map[string]string{
-		"address":    result.Results[0].FormattedAddress,
-		"postalCode": postalCode,
-	}, nil
+	return response, nil
 }
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"seicheese/internal/apperror"
+	"seicheese/internal/i18n"
+	"seicheese/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// UserExport はデータエクスポートの内容
+type UserExport struct {
+	ExportedAt time.Time              `json:"exported_at"`
+	FirebaseID string                 `json:"firebase_id"`
+	Profile    UserResponse           `json:"profile"`
+	Seichies   models.SeichySlice     `json:"seichies"`
+	Checkins   models.CheckinLogSlice `json:"checkins"`
+	Point      *models.Point          `json:"point"`
+	PointLogs  models.PointLogSlice   `json:"point_logs"`
+}
+
+// アカウント削除API
+//
+// 削除ポリシー:
+//   - 登録した聖地は他のユーザーも利用するため残し、登録者をNULLにして匿名化する
+//   - チェックイン履歴・ポイント・ポイント履歴は削除する
+//   - アバター画像は削除する
+//   - delete_firebase_user=true の場合はFirebaseのユーザーも削除する
+func (h *UserHandler) DeleteAccount(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	user, err := currentUser(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Users.DeleteAccount(ctx, user); err != nil {
+		return apperror.ErrInternal.Wrap(err)
+	}
+
+	// 以降はDBの削除が確定しているため、失敗してもログに残して処理を続ける
+	if err := h.Storage.Delete(ctx, avatarKey(user.UserID)); err != nil {
+		slog.ErrorContext(ctx, "failed to delete avatar", "user_id", user.UserID, "error", err)
+	}
+
+	firebaseUserDeleted := false
+	if c.QueryParam("delete_firebase_user") == "true" {
+		if err := h.AuthUsers.DeleteUser(ctx, user.FirebaseID); err != nil {
+			slog.ErrorContext(ctx, "failed to delete firebase user", "user_id", user.UserID, "error", err)
+		} else {
+			firebaseUserDeleted = true
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":               i18n.T(c, "ACCOUNT_DELETED"),
+		"firebase_user_deleted": firebaseUserDeleted,
+	})
+}
+
+// データエクスポートAPI（format=json でJSON、それ以外はzip）
+func (h *UserHandler) ExportData(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	user, err := currentUser(c)
+	if err != nil {
+		return err
+	}
+
+	export := UserExport{
+		ExportedAt: time.Now(),
+		FirebaseID: user.FirebaseID,
+		Profile:    newUserResponse(user),
+	}
+
+	export.Seichies, err = h.Seichies.ListByUser(ctx, user.UserID)
+	if err != nil {
+		return apperror.ErrInternal.Wrap(fmt.Errorf("fetch seichies: %w", err))
+	}
+
+	export.Checkins, err = h.Checkins.ListByUser(ctx, user.UserID)
+	if err != nil {
+		return apperror.ErrInternal.Wrap(fmt.Errorf("fetch checkin logs: %w", err))
+	}
+
+	export.Point, export.PointLogs, err = h.Users.Points(ctx, user.UserID)
+	if err != nil {
+		return apperror.ErrInternal.Wrap(fmt.Errorf("fetch points: %w", err))
+	}
+
+	// nullではなく空配列として出力する
+	if export.Seichies == nil {
+		export.Seichies = models.SeichySlice{}
+	}
+	if export.Checkins == nil {
+		export.Checkins = models.CheckinLogSlice{}
+	}
+	if export.PointLogs == nil {
+		export.PointLogs = models.PointLogSlice{}
+	}
+
+	if c.QueryParam("format") == "json" {
+		return c.JSON(http.StatusOK, export)
+	}
+
+	archive, err := buildExportArchive(export)
+	if err != nil {
+		return apperror.ErrInternal.Wrap(fmt.Errorf("build export archive: %w", err))
+	}
+
+	filename := fmt.Sprintf("seicheese-export-%d-%s.zip", user.UserID, export.ExportedAt.Format("20060102"))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Blob(http.StatusOK, "application/zip", archive)
+}
+
+// エクスポート内容を項目ごとのJSONファイルにまとめたzipを作成
+func buildExportArchive(export UserExport) ([]byte, error) {
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", map[string]interface{}{
+			"exported_at": export.ExportedAt,
+			"firebase_id": export.FirebaseID,
+			"profile":     export.Profile,
+		}},
+		{"seichies.json", export.Seichies},
+		{"checkins.json", export.Checkins},
+		{"points.json", map[string]interface{}{
+			"point": export.Point,
+			"logs":  export.PointLogs,
+		}},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -1,105 +1,311 @@
 package handler
 
 import (
-	"database/sql"
-	"log"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"seicheese/internal/apperror"
+	"seicheese/internal/auth"
+	"seicheese/internal/infrastructure/storage"
+	"seicheese/internal/middleware"
+	"seicheese/internal/repository"
 	"seicheese/models"
+	"seicheese/services"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
-	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/null/v8"
 )
 
+const (
+	maxFavoriteGenres = 10
+	maxAvatarSize     = 5 << 20 // 5MB
+	// キャッシュ回避用のクエリパラメーター
+	avatarCacheBustParam = "v"
+)
+
+// アップロードを許可するアバター画像の形式
+var allowedAvatarTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
 type UserHandler struct {
-	DB *sql.DB
+	Users       repository.UserRepository
+	UserService *services.UserService
+	Genres      repository.GenreRepository
+	Seichies    repository.SeichiRepository
+	Checkins    repository.CheckinRepository
+	Storage     storage.BlobStore
+	AuthUsers   auth.UserDeleter
 }
 
 type UserResponse struct {
-	ID        uint      `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID               uint      `json:"id"`
+	Name             string    `json:"name"`
+	AvatarURL        string    `json:"avatar_url"`
+	Bio              string    `json:"bio"`
+	FavoriteGenreIDs []int     `json:"favorite_genre_ids"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type UpdateProfileRequest struct {
+	Name             *string `json:"name" validate:"omitempty,maxrunes=50"`
+	Bio              *string `json:"bio" validate:"omitempty,maxrunes=500"`
+	FavoriteGenreIDs *[]int  `json:"favorite_genre_ids" validate:"omitempty,max=10"`
+}
+
+func newUserResponse(user *models.User) UserResponse {
+	favoriteGenreIDs := []int{}
+	if user.FavoriteGenreIds.Valid {
+		if err := user.FavoriteGenreIds.Unmarshal(&favoriteGenreIDs); err != nil {
+			slog.Error("failed to decode favorite genres", "user_id", user.UserID, "error", err)
+		}
+	}
+
+	return UserResponse{
+		ID:               user.UserID,
+		Name:             user.DisplayName.String,
+		AvatarURL:        user.AvatarURL.String,
+		Bio:              user.Bio.String,
+		FavoriteGenreIDs: favoriteGenreIDs,
+		CreatedAt:        user.CreatedAt.Time,
+		UpdatedAt:        user.UpdatedAt.Time,
+	}
 }
 
 func (h *UserHandler) RegisterUser(c echo.Context) error {
 	var req struct {
-		Name string `json:"name"`
+		Name string `json:"name" validate:"maxrunes=50"`
 	}
 
-	if err := c.Bind(&req); err != nil {
-		log.Printf("Error binding request: %v", err)
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "不正なリクエスト形式です",
-		})
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
 	}
 
-	uid := c.Get("uid").(string)
-	if uid == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "ユーザーIDが必要です",
-		})
+	uid, ok := middleware.UIDFromContext(c)
+	if !ok {
+		return apperror.ErrTokenRequired
 	}
 
-	exists, err := models.Users(
-		models.UserWhere.FirebaseID.EQ(uid),
-	).Exists(c.Request().Context(), h.DB)
+	user, created, err := h.UserService.Provision(c.Request().Context(), services.ProvisionParams{
+		FirebaseID:  uid,
+		DisplayName: req.Name,
+	})
 	if err != nil {
-		log.Printf("Error checking user existence: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "ユーザー情報の確認に失敗しました",
-		})
+		return err
 	}
-	if exists {
-		return c.JSON(http.StatusConflict, map[string]string{
-			"error": "既に登録されているユーザーです",
-		})
+	if !created {
+		return apperror.ErrUserExists
 	}
 
-	user := &models.User{
-		FirebaseID: uid,
+	return c.JSON(http.StatusCreated, newUserResponse(user))
+}
+
+func (h *UserHandler) GetUser(c echo.Context) error {
+	user, err := currentUser(c)
+	if err != nil {
+		return err
 	}
 
-	if err := user.Insert(c.Request().Context(), h.DB, boil.Infer()); err != nil {
-		log.Printf("Error inserting user: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "ユーザーの登録に失敗しました",
-		})
+	return c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// 公開プロフィール取得API
+func (h *UserHandler) GetPublicProfile(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return apperror.ErrInvalidUserID.Wrap(err)
 	}
 
-	return c.JSON(http.StatusCreated, UserResponse{
-		ID:        user.UserID,
-		CreatedAt: user.CreatedAt.Time,
-		UpdatedAt: user.UpdatedAt.Time,
-	})
+	user, err := h.Users.FindByID(c.Request().Context(), uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		return apperror.ErrUserNotFound
+	}
+	if err != nil {
+		return apperror.ErrInternal.Wrap(fmt.Errorf("fetch user: %w", err))
+	}
+
+	return c.JSON(http.StatusOK, newUserResponse(user))
 }
 
-func (h *UserHandler) GetUser(c echo.Context) error {
-	uid := c.Get("uid").(string)
-	if uid == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "ユーザーIDが必要です",
-		})
+// プロフィール更新API（指定された項目のみ更新）
+func (h *UserHandler) UpdateProfile(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req UpdateProfileRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
 	}
 
-	user, err := models.Users(
-		models.UserWhere.FirebaseID.EQ(uid),
-	).One(c.Request().Context(), h.DB)
-	if err == sql.ErrNoRows {
-		return c.JSON(http.StatusNotFound, map[string]string{
-			"error": "ユーザーが見つかりません",
-		})
+	user, err := currentUser(c)
+	if err != nil {
+		return err
 	}
+
+	var columns []string
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := h.UserService.ValidateDisplayName(name); err != nil {
+			return err
+		}
+		user.DisplayName = null.StringFrom(name)
+		columns = append(columns, models.UserColumns.DisplayName)
+	}
+
+	if req.Bio != nil {
+		bio := strings.TrimSpace(*req.Bio)
+		if err := h.UserService.ValidateBio(bio); err != nil {
+			return err
+		}
+		user.Bio = null.NewString(bio, bio != "")
+		columns = append(columns, models.UserColumns.Bio)
+	}
+
+	if req.FavoriteGenreIDs != nil {
+		genreIDs, err := h.validateFavoriteGenres(c, *req.FavoriteGenreIDs)
+		if err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(genreIDs)
+		if err != nil {
+			return apperror.ErrInternal.Wrap(err)
+		}
+		user.FavoriteGenreIds = null.JSONFrom(encoded)
+		columns = append(columns, models.UserColumns.FavoriteGenreIds)
+	}
+
+	if err := h.Users.Update(ctx, user, columns...); err != nil {
+		return apperror.ErrInternal.Wrap(fmt.Errorf("update user: %w", err))
+	}
+
+	return c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// アバター画像アップロードAPI（multipartの"avatar"フィールド）
+func (h *UserHandler) UploadAvatar(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	user, err := currentUser(c)
 	if err != nil {
-		log.Printf("Error fetching user: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "ユーザー情報の取得に失敗しました",
-		})
+		return err
 	}
 
-	return c.JSON(http.StatusOK, UserResponse{
-		ID:        user.UserID,
-		CreatedAt: user.CreatedAt.Time,
-		UpdatedAt: user.UpdatedAt.Time,
-	})
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		return apperror.ErrAvatarRequired.Wrap(err)
+	}
+	if fileHeader.Size > maxAvatarSize {
+		return apperror.ErrAvatarTooLarge
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return apperror.ErrAvatarUnreadable.Wrap(err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxAvatarSize+1))
+	if err != nil || len(data) > maxAvatarSize {
+		return apperror.ErrAvatarUnreadable.Wrap(err)
+	}
+
+	// クライアントの申告ではなく中身から形式を判定する
+	contentType := http.DetectContentType(data)
+	if !allowedAvatarTypes[contentType] {
+		return apperror.ErrAvatarUnsupportedType.WithDetails(map[string]interface{}{"content_type": contentType})
+	}
+
+	storedURL, err := h.Storage.Put(ctx, avatarKey(user.UserID), contentType, bytes.NewReader(data))
+	if err != nil {
+		return apperror.ErrInternal.Wrap(fmt.Errorf("store avatar: %w", err))
+	}
+
+	// 同じキーに上書きするため、キャッシュ回避用のクエリを付与
+	avatarURL, err := withCacheBust(storedURL, time.Now().Unix())
+	if err != nil {
+		return apperror.ErrInternal.Wrap(fmt.Errorf("avatar url: %w", err))
+	}
+	user.AvatarURL = null.StringFrom(avatarURL)
+	if err := h.Users.Update(ctx, user, models.UserColumns.AvatarURL); err != nil {
+		return apperror.ErrInternal.Wrap(fmt.Errorf("update user: %w", err))
+	}
+
+	return c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// withCacheBust はURLのクエリにバージョンを追加する
+// Firebase StorageのURL（?alt=media）のように既にクエリがある場合も壊さない
+func withCacheBust(rawURL string, version int64) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set(avatarCacheBustParam, strconv.FormatInt(version, 10))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// アバター画像削除API
+func (h *UserHandler) DeleteAvatar(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	user, err := currentUser(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Storage.Delete(ctx, avatarKey(user.UserID)); err != nil {
+		return apperror.ErrInternal.Wrap(fmt.Errorf("delete avatar: %w", err))
+	}
+
+	user.AvatarURL = null.String{}
+	if err := h.Users.Update(ctx, user, models.UserColumns.AvatarURL); err != nil {
+		return apperror.ErrInternal.Wrap(fmt.Errorf("update user: %w", err))
+	}
+
+	return c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// お気に入りジャンルの重複を除去し、存在確認を行う
+func (h *UserHandler) validateFavoriteGenres(c echo.Context, ids []int) ([]int, error) {
+	if len(ids) > maxFavoriteGenres {
+		return nil, apperror.ErrTooManyFavoriteGenres.WithDetails(map[string]interface{}{"max": maxFavoriteGenres})
+	}
+
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return unique, nil
+	}
+
+	count, err := h.Genres.CountByIDs(c.Request().Context(), unique)
+	if err != nil {
+		return nil, apperror.ErrInternal.Wrap(fmt.Errorf("count genres: %w", err))
+	}
+	if int(count) != len(unique) {
+		return nil, apperror.ErrGenreNotFound
+	}
+	return unique, nil
+}
+
+func avatarKey(userID uint) string {
+	return fmt.Sprintf("avatars/%d", userID)
 }
@@ -0,0 +1,103 @@
+// Seicheese-Backend/src/internal/i18n/i18n.go
+
+package i18n
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
+)
+
+// Lang はレスポンスの言語
+type Lang string
+
+const (
+	Japanese Lang = "ja"
+	English  Lang = "en"
+
+	// Default はAccept-Languageがない・対応していない場合の言語
+	Default = Japanese
+)
+
+// ContextKeyLang はechoのContextに言語を保存するキー
+const ContextKeyLang = "lang"
+
+const (
+	headerAcceptLanguage  = "Accept-Language"
+	headerContentLanguage = "Content-Language"
+)
+
+// 対応言語（先頭がデフォルト）
+var matcher = language.NewMatcher([]language.Tag{
+	language.Japanese,
+	language.English,
+})
+
+// ParseAcceptLanguage はAccept-Languageヘッダーから対応言語を選ぶ
+func ParseAcceptLanguage(header string) Lang {
+	if header == "" {
+		return Default
+	}
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	if index == 1 {
+		return English
+	}
+	return Japanese
+}
+
+// Middleware はAccept-Languageから言語を決めてContextに保存する
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			lang := ParseAcceptLanguage(c.Request().Header.Get(headerAcceptLanguage))
+			c.Set(ContextKeyLang, lang)
+			c.Response().Header().Set(headerContentLanguage, string(lang))
+			return next(c)
+		}
+	}
+}
+
+// FromContext はリクエストの言語を取得する
+// ミドルウェアを通っていない場合（ルーティング前のエラーなど）はヘッダーから判定する
+func FromContext(c echo.Context) Lang {
+	if lang, ok := c.Get(ContextKeyLang).(Lang); ok {
+		return lang
+	}
+	return ParseAcceptLanguage(c.Request().Header.Get(headerAcceptLanguage))
+}
+
+// Message はコードに対応するメッセージを返す
+// メッセージ中の {name} はparamsの値で置き換える
+// 指定言語に翻訳がなければデフォルト言語、それもなければ空文字を返す
+func Message(lang Lang, code string, params map[string]interface{}) string {
+	template, ok := catalogs[lang][code]
+	if !ok {
+		template, ok = catalogs[Default][code]
+		if !ok {
+			return ""
+		}
+	}
+	if len(params) == 0 {
+		return template
+	}
+
+	replacements := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		replacements = append(replacements, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+// T はリクエストの言語でメッセージを返す
+func T(c echo.Context, code string) string {
+	return Message(FromContext(c), code, nil)
+}
@@ -0,0 +1,121 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Lang
+	}{
+		{"", Japanese},
+		{"ja", Japanese},
+		{"ja-JP,ja;q=0.9", Japanese},
+		{"en", English},
+		{"en-US,en;q=0.9", English},
+		{"en-GB", English},
+		// 対応していない言語はデフォルト（日本語）
+		{"fr", Japanese},
+		{"zh-CN,ko;q=0.8", Japanese},
+		// 対応している言語があればそちらを選ぶ
+		{"fr,en;q=0.5", English},
+		{"*", Japanese},
+		// 不正な形式はデフォルト
+		{"en;q=abc;;", Japanese},
+	}
+	for _, tt := range tests {
+		if got := ParseAcceptLanguage(tt.header); got != tt.want {
+			t.Errorf("ParseAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	if got := Message(English, "USER_NOT_FOUND", nil); got != "The user was not found." {
+		t.Errorf("Message(en) = %q", got)
+	}
+	if got := Message(English, "FIELD_TOO_LONG", map[string]interface{}{"max": 50}); got != "Must be at most 50 characters." {
+		t.Errorf("Message(en, params) = %q", got)
+	}
+
+	// カタログにないコードは空文字（呼び出し側で元のメッセージを使う）
+	if got := Message(English, "NO_SUCH_CODE", nil); got != "" {
+		t.Errorf("Message(missing key) = %q, want empty", got)
+	}
+	// 対応していない言語はデフォルトの言語のメッセージ
+	if got := Message(Lang("fr"), "USER_NOT_FOUND", nil); got != catalogs[Default]["USER_NOT_FOUND"] {
+		t.Errorf("Message(fr) = %q, want default language", got)
+	}
+
+	// 翻訳がないコードはデフォルトの言語のメッセージ
+	catalogs[Default]["TEST_ONLY_JAPANESE"] = "日本語のみ"
+	t.Cleanup(func() { delete(catalogs[Default], "TEST_ONLY_JAPANESE") })
+	if got := Message(English, "TEST_ONLY_JAPANESE", nil); got != "日本語のみ" {
+		t.Errorf("Message(en, untranslated) = %q, want default language", got)
+	}
+}
+
+var placeholder = regexp.MustCompile(`\{[a-z_]+\}`)
+
+// 日本語と英語のカタログに同じコードと同じプレースホルダーがあること
+func TestCatalogParity(t *testing.T) {
+	for code, ja := range catalogs[Japanese] {
+		en, ok := catalogs[English][code]
+		if !ok {
+			t.Errorf("%s: missing English message", code)
+			continue
+		}
+		if got, want := placeholders(en), placeholders(ja); !slices.Equal(got, want) {
+			t.Errorf("%s: English placeholders %v, want %v", code, got, want)
+		}
+	}
+	for code := range catalogs[English] {
+		if _, ok := catalogs[Japanese][code]; !ok {
+			t.Errorf("%s: missing Japanese message", code)
+		}
+	}
+}
+
+func placeholders(message string) []string {
+	found := placeholder.FindAllString(message, -1)
+	slices.Sort(found)
+	return found
+}
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, T(c, "USER_NOT_FOUND"))
+	})
+
+	tests := []struct {
+		header   string
+		wantLang string
+		wantBody string
+	}{
+		{"en-US", "en", "The user was not found."},
+		{"fr", "ja", "ユーザーが見つかりません"},
+		{"", "ja", "ユーザーが見つかりません"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", tt.header)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Content-Language"); got != tt.wantLang {
+			t.Errorf("Accept-Language %q: Content-Language = %q, want %q", tt.header, got, tt.wantLang)
+		}
+		if rec.Body.String() != tt.wantBody {
+			t.Errorf("Accept-Language %q: body = %q, want %q", tt.header, rec.Body.String(), tt.wantBody)
+		}
+	}
+}
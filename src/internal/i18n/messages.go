@@ -0,0 +1,130 @@
+// Seicheese-Backend/src/internal/i18n/messages.go
+
+package i18n
+
+// catalogs は言語ごとのメッセージ（キーはエラーコードなど）
+// 新しいコードを追加するときは両方の言語に追加すること
+var catalogs = map[Lang]map[string]string{
+	Japanese: {
+		// 共通
+		"INVALID_REQUEST":          "不正なリクエスト形式です",
+		"NOT_FOUND":                "リソースが見つかりません",
+		"INTERNAL_ERROR":           "サーバーでエラーが発生しました",
+		"BAD_REQUEST":              "不正なリクエストです",
+		"UNAUTHORIZED":             "認証が必要です",
+		"FORBIDDEN":                "アクセスが許可されていません",
+		"METHOD_NOT_ALLOWED":       "許可されていないメソッドです",
+		"REQUEST_ENTITY_TOO_LARGE": "リクエストのサイズが大きすぎます",
+		"UNSUPPORTED_MEDIA_TYPE":   "対応していない形式です",
+		"TOO_MANY_REQUESTS":        "リクエストが多すぎます。しばらくしてから再度お試しください",
+
+		// 入力値の検証（項目ごと）
+		"VALIDATION_FAILED":        "入力内容に誤りがあります",
+		"FIELD_INVALID":            "入力値が不正です",
+		"FIELD_REQUIRED":           "必須項目です",
+		"FIELD_TOO_LONG":           "{max}文字以内で入力してください",
+		"FIELD_TOO_SHORT":          "{min}文字以上で入力してください",
+		"FIELD_TOO_MANY":           "{max}件までです",
+		"FIELD_TOO_FEW":            "{min}件以上指定してください",
+		"FIELD_TOO_LARGE":          "{max}以下の値を指定してください",
+		"FIELD_TOO_SMALL":          "{min}以上の値を指定してください",
+		"FIELD_INVALID_COORDINATE": "緯度・経度の値が不正です",
+		"FIELD_OUT_OF_JAPAN":       "日本の範囲外の位置です",
+		"FIELD_NOT_FOUND":          "指定されたデータが存在しません",
+
+		// 認証
+		"TOKEN_REQUIRED":          "認証トークンがありません",
+		"INVALID_TOKEN_FORMAT":    "トークン形式が無効です",
+		"INVALID_TOKEN":           "無効なトークンです",
+		"VERSION_REQUIRED":        "バージョン情報が必要です",
+		"UNSUPPORTED_APP_VERSION": "サポートされていないアプリバージョンです",
+		"UPGRADE_REQUIRED":        "このバージョンのアプリはご利用いただけません。{min_version}以上にアップデートしてください",
+		"AUTH_SUCCEEDED":          "認証成功",
+		"SIGN_UP_SUCCEEDED":       "ユーザー登録成功",
+
+		// ユーザー
+		"INVALID_USER_ID":                 "ユーザーIDが不正です",
+		"USER_NOT_FOUND":                  "ユーザーが見つかりません",
+		"USER_NOT_REGISTERED":             "ユーザー登録が完了していません。サインアップしてください。",
+		"USER_EXISTS":                     "既に登録済みのユーザーです。サインインしてください。",
+		"DISPLAY_NAME_REQUIRED":           "表示名を入力してください",
+		"DISPLAY_NAME_TOO_LONG":           "表示名は{max}文字以内で入力してください",
+		"DISPLAY_NAME_INVALID_CHARACTERS": "表示名に使用できない文字が含まれています",
+		"DISPLAY_NAME_INAPPROPRIATE":      "表示名に不適切な表現が含まれています",
+		"BIO_TOO_LONG":                    "自己紹介は{max}文字以内で入力してください",
+		"BIO_INAPPROPRIATE":               "自己紹介に不適切な表現が含まれています",
+		"TOO_MANY_FAVORITE_GENRES":        "お気に入りジャンルは{max}件までです",
+		"GENRE_NOT_FOUND":                 "存在しないジャンルが含まれています",
+		"AVATAR_REQUIRED":                 "画像ファイルが必要です",
+		"AVATAR_TOO_LARGE":                "画像サイズが大きすぎます（上限5MB）",
+		"AVATAR_UNREADABLE":               "画像ファイルを読み込めません",
+		"AVATAR_UNSUPPORTED_TYPE":         "対応していない画像形式です（JPEG・PNG・WebPのみ）",
+		"ACCOUNT_DELETED":                 "アカウントを削除しました",
+
+		// 聖地・作品・チェックイン
+		"GEOCODING_FAILED":  "住所の取得に失敗しました",
+		"ADDRESS_NOT_FOUND": "指定された位置の住所が見つかりません",
+		"CHECKIN_SUCCEEDED": "チェックイン成功",
+	},
+	English: {
+		// 共通
+		"INVALID_REQUEST":          "The request is malformed.",
+		"NOT_FOUND":                "The requested resource was not found.",
+		"INTERNAL_ERROR":           "An internal server error occurred.",
+		"BAD_REQUEST":              "The request is invalid.",
+		"UNAUTHORIZED":             "Authentication is required.",
+		"FORBIDDEN":                "You are not allowed to access this resource.",
+		"METHOD_NOT_ALLOWED":       "This method is not allowed.",
+		"REQUEST_ENTITY_TOO_LARGE": "The request is too large.",
+		"UNSUPPORTED_MEDIA_TYPE":   "This media type is not supported.",
+		"TOO_MANY_REQUESTS":        "Too many requests. Please try again later.",
+
+		// 入力値の検証（項目ごと）
+		"VALIDATION_FAILED":        "Some fields are invalid.",
+		"FIELD_INVALID":            "This value is invalid.",
+		"FIELD_REQUIRED":           "This field is required.",
+		"FIELD_TOO_LONG":           "Must be at most {max} characters.",
+		"FIELD_TOO_SHORT":          "Must be at least {min} characters.",
+		"FIELD_TOO_MANY":           "Must have at most {max} items.",
+		"FIELD_TOO_FEW":            "Must have at least {min} items.",
+		"FIELD_TOO_LARGE":          "Must be {max} or less.",
+		"FIELD_TOO_SMALL":          "Must be {min} or more.",
+		"FIELD_INVALID_COORDINATE": "The latitude or longitude is invalid.",
+		"FIELD_OUT_OF_JAPAN":       "The location is outside Japan.",
+		"FIELD_NOT_FOUND":          "The referenced item does not exist.",
+
+		// 認証
+		"TOKEN_REQUIRED":          "An authentication token is required.",
+		"INVALID_TOKEN_FORMAT":    "The token format is invalid.",
+		"INVALID_TOKEN":           "The token is invalid.",
+		"VERSION_REQUIRED":        "The app version is required.",
+		"UNSUPPORTED_APP_VERSION": "This app version is not supported.",
+		"UPGRADE_REQUIRED":        "This version of the app is no longer supported. Please update to {min_version} or later.",
+		"AUTH_SUCCEEDED":          "Authenticated successfully.",
+		"SIGN_UP_SUCCEEDED":       "Signed up successfully.",
+
+		// ユーザー
+		"INVALID_USER_ID":                 "The user ID is invalid.",
+		"USER_NOT_FOUND":                  "The user was not found.",
+		"USER_NOT_REGISTERED":             "Registration is not complete. Please sign up.",
+		"USER_EXISTS":                     "This user is already registered. Please sign in.",
+		"DISPLAY_NAME_REQUIRED":           "Please enter a display name.",
+		"DISPLAY_NAME_TOO_LONG":           "The display name must be at most {max} characters.",
+		"DISPLAY_NAME_INVALID_CHARACTERS": "The display name contains characters that are not allowed.",
+		"DISPLAY_NAME_INAPPROPRIATE":      "The display name contains inappropriate language.",
+		"BIO_TOO_LONG":                    "The bio must be at most {max} characters.",
+		"BIO_INAPPROPRIATE":               "The bio contains inappropriate language.",
+		"TOO_MANY_FAVORITE_GENRES":        "You can choose up to {max} favorite genres.",
+		"GENRE_NOT_FOUND":                 "Some of the genres do not exist.",
+		"AVATAR_REQUIRED":                 "An image file is required.",
+		"AVATAR_TOO_LARGE":                "The image is too large (max 5MB).",
+		"AVATAR_UNREADABLE":               "The image file could not be read.",
+		"AVATAR_UNSUPPORTED_TYPE":         "Unsupported image format (JPEG, PNG and WebP only).",
+		"ACCOUNT_DELETED":                 "Your account has been deleted.",
+
+		// 聖地・作品・チェックイン
+		"GEOCODING_FAILED":  "Failed to look up the address.",
+		"ADDRESS_NOT_FOUND": "No address was found for the location.",
+		"CHECKIN_SUCCEEDED": "Checked in successfully.",
+	},
+}
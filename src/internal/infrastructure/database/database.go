@@ -2,30 +2,35 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
-	"os"
 
 	_ "github.com/go-sql-driver/mysql"
 )
 
 // データベース接続の設定構造体
 type DBConfig struct {
-	User     string
-	Password string
-	Host     string
-	Port     string
-	Name     string
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Name     string `yaml:"name"`
 }
 
-// NewDBConfig は環境変数からデータベース設定を作成
-func NewDBConfig() *DBConfig {
-	return &DBConfig{
-		User:     os.Getenv("DB_USER"),
-		Password: os.Getenv("DB_PASS"),
-		Host:     os.Getenv("DB_HOST"),
-		Port:     os.Getenv("DB_PORT"),
-		Name:     os.Getenv("DB_NAME"),
+// Validate は接続に必要な設定がそろっているか検証する（パスワードは空でもよい）
+func (c *DBConfig) Validate() error {
+	var errs []error
+	for _, required := range []struct{ name, value string }{
+		{"DB_USER", c.User},
+		{"DB_HOST", c.Host},
+		{"DB_PORT", c.Port},
+		{"DB_NAME", c.Name},
+	} {
+		if required.value == "" {
+			errs = append(errs, fmt.Errorf("%s is required", required.name))
+		}
 	}
+	return errors.Join(errs...)
 }
 
 // InitializeDB はデータベース接続を初期化
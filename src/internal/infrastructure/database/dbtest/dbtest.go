@@ -0,0 +1,246 @@
+// Package dbtest は使い捨てのMySQLデータベースを用意するテスト用ヘルパー
+//
+// 接続先のMySQLサーバーは次の順で決まる（どちらもない場合はテストをスキップする）
+//   - TEST_DB_DSN: 既存のMySQLサーバーのDSN（例: root:pass@tcp(127.0.0.1:3306)/）
+//   - PATH上のmysqld: 一時ディレクトリにデータを初期化し、ネットワークを使わずソケット接続のみで起動する
+//
+// テストごとにランダムな名前のデータベースを作成し、テスト終了時に削除する
+// mysqldを起動した場合はパッケージのTestMainでMainを呼び出して停止させる
+package dbtest
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	schema "seicheese/database"
+	"seicheese/internal/infrastructure/database"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pressly/goose/v3"
+)
+
+// EnvDSN は既存のMySQLサーバーを使う場合のDSNを指定する環境変数
+const EnvDSN = "TEST_DB_DSN"
+
+// ローカルのmysqldの起動を待つ時間
+const startTimeout = 60 * time.Second
+
+var errNoServer = errors.New("dbtest: no MySQL server available")
+
+// server はテストで共有するMySQLサーバー
+type server struct {
+	config *mysql.Config
+
+	// ローカルで起動した場合のみ設定される
+	cmd *exec.Cmd
+	dir string
+}
+
+var (
+	serverOnce sync.Once
+	shared     *server
+	sharedErr  error
+)
+
+func getServer() (*server, error) {
+	serverOnce.Do(func() {
+		shared, sharedErr = startServer()
+	})
+	return shared, sharedErr
+}
+
+func startServer() (*server, error) {
+	if dsn := os.Getenv(EnvDSN); dsn != "" {
+		config, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("dbtest: invalid %s: %w", EnvDSN, err)
+		}
+		return &server{config: config}, nil
+	}
+
+	mysqld, err := exec.LookPath("mysqld")
+	if err != nil {
+		return nil, errNoServer
+	}
+	return startLocalServer(mysqld)
+}
+
+// startLocalServer は一時ディレクトリにデータディレクトリを作成してmysqldを起動する
+func startLocalServer(mysqld string) (*server, error) {
+	dir, err := os.MkdirTemp("", "seicheese-mysql-")
+	if err != nil {
+		return nil, fmt.Errorf("dbtest: create temp dir: %w", err)
+	}
+
+	args := []string{"--no-defaults", "--datadir=" + filepath.Join(dir, "data")}
+	if os.Geteuid() == 0 {
+		// mysqldはrootでの起動を明示しないと拒否する
+		args = append(args, "--user=root")
+	}
+
+	initArgs := append(append([]string{}, args...), "--initialize-insecure", "--log-error="+filepath.Join(dir, "init.log"))
+	if out, err := exec.Command(mysqld, initArgs...).CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("dbtest: initialize mysqld: %w: %s", err, out)
+	}
+
+	socket := filepath.Join(dir, "mysqld.sock")
+	cmd := exec.Command(mysqld, append(args,
+		"--socket="+socket,
+		"--skip-networking",
+		"--mysqlx=OFF",
+		"--pid-file="+filepath.Join(dir, "mysqld.pid"),
+		"--log-error="+filepath.Join(dir, "error.log"),
+	)...)
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("dbtest: start mysqld: %w", err)
+	}
+
+	config := mysql.NewConfig()
+	config.User = "root"
+	config.Net = "unix"
+	config.Addr = socket
+	s := &server{config: config, cmd: cmd, dir: dir}
+
+	if err := s.waitReady(); err != nil {
+		s.stop()
+		return nil, err
+	}
+	return s, nil
+}
+
+// waitReady はmysqldが接続を受け付けるまで待つ
+func (s *server) waitReady() error {
+	deadline := time.Now().Add(startTimeout)
+	for {
+		db, err := sql.Open("mysql", s.dsn(""))
+		if err == nil {
+			err = db.Ping()
+			db.Close()
+		}
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			log, _ := os.ReadFile(filepath.Join(s.dir, "error.log"))
+			return fmt.Errorf("dbtest: mysqld did not become ready: %w: %s", err, log)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+func (s *server) stop() {
+	if s.cmd == nil {
+		return
+	}
+	s.cmd.Process.Signal(syscall.SIGTERM)
+	s.cmd.Wait()
+	os.RemoveAll(s.dir)
+}
+
+// dsn はdbNameに接続するDSNを返す（アプリケーションと同じくparseTimeとAsia/Tokyoを使う）
+func (s *server) dsn(dbName string) string {
+	config := s.config.Clone()
+	config.DBName = dbName
+	config.ParseTime = true
+	if loc, err := time.LoadLocation("Asia/Tokyo"); err == nil {
+		config.Loc = loc
+	}
+	return config.FormatDSN()
+}
+
+// Main はテストを実行し、ローカルで起動したmysqldを停止して終了する
+//
+//	func TestMain(m *testing.M) { dbtest.Main(m) }
+func Main(m *testing.M) {
+	code := m.Run()
+	if shared != nil {
+		shared.stop()
+	}
+	os.Exit(code)
+}
+
+// New は空のデータベースを作成して接続を返す（テスト終了時に削除される）
+func New(t testing.TB) *sql.DB {
+	t.Helper()
+
+	s, err := getServer()
+	if errors.Is(err, errNoServer) {
+		t.Skipf("skipping: set %s or install mysqld to run database tests", EnvDSN)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	admin, err := sql.Open("mysql", s.dsn(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	name := "seicheese_test_" + randomSuffix(t)
+	if _, err := admin.Exec(fmt.Sprintf("CREATE DATABASE `%s` CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci", name)); err != nil {
+		t.Fatalf("dbtest: create database: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(fmt.Sprintf("DROP DATABASE `%s`", name)); err != nil {
+			t.Errorf("dbtest: drop database: %v", err)
+		}
+	})
+
+	db, err := sql.Open("mysql", s.dsn(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// データベースの削除より先に接続を閉じる
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// NewMigrated はすべてのマイグレーションを適用したデータベースを返す
+func NewMigrated(t testing.TB) *sql.DB {
+	t.Helper()
+
+	db := New(t)
+	if _, err := NewProvider(t, db).Up(context.Background()); err != nil {
+		t.Fatalf("dbtest: migrate up: %v", err)
+	}
+	return db
+}
+
+// NewProvider はアプリケーションに埋め込まれたマイグレーションを適用するgooseのProviderを返す
+func NewProvider(t testing.TB, db *sql.DB) *goose.Provider {
+	t.Helper()
+
+	provider, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("dbtest: create goose provider: %v", err)
+	}
+	return provider
+}
+
+// MigrationsFS はアプリケーションに埋め込まれたマイグレーションを返す
+func MigrationsFS() fs.FS {
+	return schema.Migrations()
+}
+
+func randomSuffix(t testing.TB) string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+
+	schema "seicheese/database"
+
+	"github.com/pressly/goose/v3"
+)
+
+// MigrateCommands はMigrateで実行できるコマンド
+var MigrateCommands = []string{"up", "down", "status", "redo"}
+
+// NewMigrator は埋め込まれたマイグレーションを適用するgooseのProviderを作成
+func NewMigrator(db *sql.DB) (*goose.Provider, error) {
+	return goose.NewProvider(goose.DialectMySQL, db, schema.Migrations())
+}
+
+// Migrate はマイグレーションのコマンドを実行し、結果をwに出力する
+//   - up: 未適用のマイグレーションをすべて適用する
+//   - down: 最後に適用したマイグレーションを1つ戻す
+//   - status: 各マイグレーションの適用状況を表示する
+//   - redo: 最後に適用したマイグレーションを戻して再適用する
+func Migrate(ctx context.Context, db *sql.DB, command string, w io.Writer) error {
+	if !isMigrateCommand(command) {
+		return fmt.Errorf("unknown migrate command %q (expected one of %v)", command, MigrateCommands)
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	switch command {
+	case "up":
+		results, err := migrator.Up(ctx)
+		printResults(w, results...)
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(w, "no migrations to apply")
+		}
+		return nil
+
+	case "down":
+		result, err := migrator.Down(ctx)
+		if errors.Is(err, goose.ErrNoNextVersion) {
+			fmt.Fprintln(w, "no migrations to roll back")
+			return nil
+		}
+		printResults(w, result)
+		return err
+
+	case "redo":
+		down, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		printResults(w, down)
+		up, err := migrator.UpByOne(ctx)
+		printResults(w, up)
+		return err
+
+	default: // status
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%-25s %s\n", "Applied At", "Migration")
+		for _, status := range statuses {
+			appliedAt := "Pending"
+			if status.State == goose.StateApplied {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%-25s %s\n", appliedAt, status.Source.Path)
+		}
+		return nil
+	}
+}
+
+func isMigrateCommand(command string) bool {
+	for _, c := range MigrateCommands {
+		if c == command {
+			return true
+		}
+	}
+	return false
+}
+
+func printResults(w io.Writer, results ...*goose.MigrationResult) {
+	for _, result := range results {
+		if result != nil {
+			fmt.Fprintln(w, result)
+		}
+	}
+}
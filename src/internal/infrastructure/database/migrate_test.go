@@ -0,0 +1,79 @@
+package database_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"seicheese/internal/infrastructure/database"
+	"seicheese/internal/infrastructure/database/dbtest"
+)
+
+func TestMain(m *testing.M) { dbtest.Main(m) }
+
+func TestMigrateUnknownCommand(t *testing.T) {
+	err := database.Migrate(context.Background(), nil, "sideways", &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "unknown migrate command") {
+		t.Errorf("Migrate(sideways) error = %v, want unknown command", err)
+	}
+}
+
+func TestMigrateCommands(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.New(t)
+
+	run := func(command string) string {
+		t.Helper()
+		var out bytes.Buffer
+		if err := database.Migrate(ctx, db, command, &out); err != nil {
+			t.Fatalf("migrate %s: %v\n%s", command, err, out.String())
+		}
+		return out.String()
+	}
+
+	if out := run("status"); !strings.Contains(out, "Pending") {
+		t.Errorf("status before up does not list pending migrations:\n%s", out)
+	}
+	if out := run("up"); !strings.Contains(out, "create_users_table") {
+		t.Errorf("up did not apply the first migration:\n%s", out)
+	}
+	if out := run("up"); !strings.Contains(out, "no migrations to apply") {
+		t.Errorf("second up applied migrations:\n%s", out)
+	}
+	if out := run("status"); strings.Contains(out, "Pending") {
+		t.Errorf("status after up lists pending migrations:\n%s", out)
+	}
+
+	// redoは最後のマイグレーションを戻してから再適用する
+	out := run("redo")
+	if down, up := strings.Index(out, " down "), strings.Index(out, " up "); down < 0 || up < down {
+		t.Errorf("redo did not roll back and reapply:\n%s", out)
+	}
+
+	run("down")
+	if out := run("status"); strings.Count(out, "Pending") != 1 {
+		t.Errorf("status after down should list one pending migration:\n%s", out)
+	}
+}
+
+func TestMigrateAllDown(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.NewMigrated(t)
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.DownTo(ctx, 0); err != nil {
+		t.Fatalf("down to 0: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := database.Migrate(ctx, db, "down", &out); err != nil {
+		t.Fatalf("down with nothing applied: %v", err)
+	}
+	if !strings.Contains(out.String(), "no migrations to roll back") {
+		t.Errorf("down with nothing applied printed:\n%s", out.String())
+	}
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "seicheese/internal/infrastructure/database"
+
+var tracer = otel.Tracer(tracerName)
+
+// DB はsqlboilerのクエリの実行とトランザクションの開始ができる接続
+// *sql.DBとTraceの戻り値がこのインターフェースを満たす
+type DB interface {
+	boil.ContextExecutor
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+var _ DB = (*sql.DB)(nil)
+
+// Trace はクエリごとにOpenTelemetryのスパンを記録する接続を返す
+// スパンにはプレースホルダーを含むSQLだけを記録し、引数の値は記録しない
+func Trace(db *sql.DB) DB {
+	return &tracedDB{tracedExecutor: tracedExecutor{exec: db}, db: db}
+}
+
+type tracedDB struct {
+	tracedExecutor
+	db *sql.DB
+}
+
+func (d *tracedDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return d.db.BeginTx(ctx, opts)
+}
+
+// tracedExecutor はboil.ContextExecutorの呼び出しごとにスパンを記録する
+type tracedExecutor struct {
+	exec boil.ContextExecutor
+	// トランザクション内の場合はそのスパン（呼び出し側のctxに関係なくクエリのスパンの親にする）
+	tx trace.Span
+}
+
+func (e tracedExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return e.ExecContext(context.Background(), query, args...)
+}
+
+func (e tracedExecutor) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return e.QueryContext(context.Background(), query, args...)
+}
+
+func (e tracedExecutor) QueryRow(query string, args ...interface{}) *sql.Row {
+	return e.QueryRowContext(context.Background(), query, args...)
+}
+
+func (e tracedExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := e.startSpan(ctx, query)
+	result, err := e.exec.ExecContext(ctx, query, args...)
+	endSpan(span, err)
+	return result, err
+}
+
+// QueryContext のスパンは結果の読み出しを含まない（クエリの実行までの時間）
+func (e tracedExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := e.startSpan(ctx, query)
+	rows, err := e.exec.QueryContext(ctx, query, args...)
+	endSpan(span, err)
+	return rows, err
+}
+
+func (e tracedExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := e.startSpan(ctx, query)
+	row := e.exec.QueryRowContext(ctx, query, args...)
+	endSpan(span, row.Err())
+	return row
+}
+
+// startSpan はSQLの種類（SELECT・INSERTなど）を名前にしたスパンを開始する
+func (e tracedExecutor) startSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	if e.tx != nil {
+		ctx = trace.ContextWithSpan(ctx, e.tx)
+	}
+	operation := "query"
+	if fields := strings.Fields(query); len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+	return tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMySQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(query),
+		),
+	)
+}
+
+// endSpan はエラーをスパンに記録して終了する（該当なしは正常として扱う）
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
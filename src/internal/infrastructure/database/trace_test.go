@@ -0,0 +1,89 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"seicheese/internal/infrastructure/database"
+	"seicheese/internal/infrastructure/database/dbtest"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var (
+	recorderOnce sync.Once
+	recorder     *tracetest.SpanRecorder
+)
+
+// spanRecorder はグローバルのプロバイダーに記録用のプロセッサーを登録する
+// パッケージ変数のトレーサーは最初に登録したプロバイダーに委譲されるため、登録は1回だけ行う
+func spanRecorder() *tracetest.SpanRecorder {
+	recorderOnce.Do(func() {
+		recorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	})
+	return recorder
+}
+
+func TestTraceRecordsQueriesInTransaction(t *testing.T) {
+	recorder := spanRecorder()
+	db := database.Trace(dbtest.New(t))
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+
+	var value int
+	if err := db.QueryRowContext(ctx, "SELECT 1").Scan(&value); err != nil {
+		t.Fatal(err)
+	}
+
+	errRollback := errors.New("rollback")
+	err := database.WithTx(ctx, db, func(tx boil.ContextExecutor) error {
+		if _, err := tx.ExecContext(ctx, "CREATE TABLE traced (id INT PRIMARY KEY)"); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO missing_table VALUES (1)"); err == nil {
+			t.Error("insert into missing table: expected error")
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("WithTx error = %v, want %v", err, errRollback)
+	}
+	parent.End()
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID() == parent.SpanContext().TraceID() {
+			spans[span.Name()] = span
+		}
+	}
+	for _, name := range []string{"SELECT", "transaction", "CREATE", "INSERT"} {
+		if spans[name] == nil {
+			t.Fatalf("span %q was not recorded (got %v)", name, spans)
+		}
+	}
+
+	if got := spans["SELECT"].Parent().SpanID(); got != parent.SpanContext().SpanID() {
+		t.Errorf("SELECT parent = %s, want request span", got)
+	}
+	// トランザクション内のクエリはトランザクションのスパンの子になる
+	tx := spans["transaction"]
+	for _, name := range []string{"CREATE", "INSERT"} {
+		if got := spans[name].Parent().SpanID(); got != tx.SpanContext().SpanID() {
+			t.Errorf("%s parent = %s, want transaction span", name, got)
+		}
+	}
+	if spans["INSERT"].Status().Code != codes.Error || tx.Status().Code != codes.Error {
+		t.Errorf("failed query status = %v, transaction status = %v; want Error", spans["INSERT"].Status(), tx.Status())
+	}
+	for _, attr := range spans["CREATE"].Attributes() {
+		if attr.Key == "db.query.text" && attr.Value.AsString() != "CREATE TABLE traced (id INT PRIMARY KEY)" {
+			t.Errorf("db.query.text = %q", attr.Value.AsString())
+		}
+	}
+}
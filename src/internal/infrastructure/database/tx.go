@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// WithTx はfnをトランザクション内で実行する
+// fnがエラーを返した場合やpanicした場合はロールバックし、それ以外はコミットする
+// fnが返したエラーはそのまま返す（apperrorなどの判定に使えるようにラップしない）
+// トランザクション全体を1つのスパンとし、fnに渡す接続はクエリごとのスパンを記録する
+func WithTx(ctx context.Context, db DB, fn func(tx boil.ContextExecutor) error) (err error) {
+	ctx, span := tracer.Start(ctx, "transaction")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tracedExecutor{exec: tx, tx: span}); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (failed to rollback transaction: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
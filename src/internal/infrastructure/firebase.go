@@ -3,23 +3,32 @@ package firebase
 import (
 	"context"
 	"fmt"
-	"os"
 
 	firebase "firebase.google.com/go/v4"
 	"google.golang.org/api/option"
 )
 
-func InitializeFirebaseApp() (*firebase.App, error) {
+// InitializeFirebaseApp はcredPath（FIREBASE_SDK_PATH）の認証情報でFirebaseアプリを初期化
+// emulatorがtrueの場合（Firebase Auth Emulatorを使う場合）は認証情報なしでも初期化できる
+func InitializeFirebaseApp(credPath, projectID string, emulator bool) (*firebase.App, error) {
 	ctx := context.Background()
 
-	// 環境変数から認証情報ファイルのパスを取得
-	credPath := os.Getenv("FIREBASE_SDK_PATH")
-	if credPath == "" {
+	var config *firebase.Config
+	if projectID != "" {
+		config = &firebase.Config{ProjectID: projectID}
+	}
+
+	var opt option.ClientOption
+	switch {
+	case credPath != "":
+		opt = option.WithCredentialsFile(credPath)
+	case emulator:
+		opt = option.WithoutAuthentication()
+	default:
 		return nil, fmt.Errorf("FIREBASE_SDK_PATHの環境変数が設定されていません")
 	}
-	opt := option.WithCredentialsFile(credPath)
 
-	app, err := firebase.NewApp(ctx, nil, opt)
+	app, err := firebase.NewApp(ctx, config, opt)
 	if err != nil {
 		return nil, fmt.Errorf("firebase initialization error: %v", err)
 	}
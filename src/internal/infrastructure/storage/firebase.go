@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	gcs "cloud.google.com/go/storage"
+)
+
+// FirebaseBlobStore はFirebase Storage（Cloud Storage）に保存するBlobStore
+type FirebaseBlobStore struct {
+	bucket     *gcs.BucketHandle
+	bucketName string
+}
+
+func NewFirebaseBlobStore(bucket *gcs.BucketHandle, bucketName string) *FirebaseBlobStore {
+	return &FirebaseBlobStore{
+		bucket:     bucket,
+		bucketName: bucketName,
+	}
+}
+
+func (s *FirebaseBlobStore) Put(ctx context.Context, key, contentType string, r io.Reader) (string, error) {
+	w := s.bucket.Object(key).NewWriter(ctx)
+	w.ContentType = contentType
+	w.CacheControl = "public, max-age=3600"
+
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return "", fmt.Errorf("failed to upload blob: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("failed to upload blob: %v", err)
+	}
+
+	// Firebase Storageのダウンロード用URL（公開可否はStorageのセキュリティルールで制御）
+	return fmt.Sprintf(
+		"https://firebasestorage.googleapis.com/v0/b/%s/o/%s?alt=media",
+		s.bucketName, strings.ReplaceAll(url.PathEscape(key), "/", "%2F"),
+	), nil
+}
+
+func (s *FirebaseBlobStore) Delete(ctx context.Context, key string) error {
+	err := s.bucket.Object(key).Delete(ctx)
+	if err != nil && !errors.Is(err, gcs.ErrObjectNotExist) {
+		return fmt.Errorf("failed to delete blob: %v", err)
+	}
+	return nil
+}
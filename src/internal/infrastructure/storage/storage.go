@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	firebase "firebase.google.com/go/v4"
+)
+
+// BlobStore はアバター画像などのバイナリデータを保存するストレージ
+type BlobStore interface {
+	// Put はkeyにデータを保存し、公開URLを返す（同じkeyは上書きされる）
+	Put(ctx context.Context, key, contentType string, r io.Reader) (string, error)
+	// Delete はkeyのデータを削除する（存在しない場合はエラーにしない）
+	Delete(ctx context.Context, key string) error
+}
+
+// ストレージ設定の構造体
+type Config struct {
+	// Firebase Storageのバケット名（空の場合はローカルディスクを使用）
+	Bucket string `yaml:"bucket"`
+	// ローカル保存先ディレクトリ
+	LocalDir string `yaml:"local_dir"`
+	// ローカル保存時の公開URLのプレフィックス
+	PublicBaseURL string `yaml:"public_base_url"`
+}
+
+// InitializeBlobStore は設定に応じたBlobStoreを初期化
+func InitializeBlobStore(ctx context.Context, app *firebase.App, config *Config) (BlobStore, error) {
+	if config.Bucket == "" {
+		return NewLocalBlobStore(config.LocalDir, config.PublicBaseURL)
+	}
+
+	client, err := app.Storage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage client: %v", err)
+	}
+	bucket, err := client.Bucket(config.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bucket %s: %v", config.Bucket, err)
+	}
+	return NewFirebaseBlobStore(bucket, config.Bucket), nil
+}
@@ -0,0 +1,77 @@
+// Package logging はlog/slogによるJSON形式の構造化ログを提供する
+//
+//   - リクエストIDをcontextで受け渡し、*Context系の関数（slog.InfoContextなど）で出力したログに自動で付与する
+//     （contextにトレースのスパンがあればトレースIDも付与する）
+//   - トークン・Firebase ID・詳細な位置情報は出力前に伏せる（redact.go）
+//   - echoのミドルウェアでリクエストIDの採番とアクセスログの出力を行う（middleware.go）
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ParseLevel はログレベル（debug, info, warn, error）を解釈する
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (expected debug, info, warn or error)", level)
+}
+
+// New はwにJSON形式で出力するロガーを作成
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: Redact,
+	})
+	return slog.New(&contextHandler{handler})
+}
+
+type requestIDKey struct{}
+
+// WithRequestID はリクエストIDを保存したcontextを返す
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext はcontextに保存されたリクエストIDを返す（ない場合は空文字）
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// contextHandler はcontextのリクエストIDとトレースID・スパンIDをログに付与する
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{h.Handler.WithGroup(name)}
+}
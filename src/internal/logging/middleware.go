@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// リクエストIDをやり取りするヘッダー（クライアントやロードバランサーが付与したものを引き継ぐ）
+const HeaderRequestID = echo.HeaderXRequestID
+
+// 引き継ぐリクエストIDの形式（ログの改ざんを防ぐため、それ以外は採番し直す）
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// 死活監視などの頻繁に呼ばれるルートはDEBUGレベルで出力する
+var quietRoutes = map[string]bool{
+	"/health": true,
+	"/livez":  true,
+	"/readyz": true,
+}
+
+// Middleware はリクエストIDを採番してcontextとレスポンスヘッダーに設定し、処理後にアクセスログを出力する
+// エラーはここでHTTPErrorHandlerに渡し、確定したステータスを記録する
+// アクセスログは後続のミドルウェアが設定したcontextで出力する（tracing.Middlewareのスパンのtrace_id・span_idが付く）
+func Middleware(logger *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+
+			requestID := req.Header.Get(HeaderRequestID)
+			if !requestIDPattern.MatchString(requestID) {
+				requestID = newRequestID()
+			}
+			ctx := WithRequestID(req.Context(), requestID)
+			c.SetRequest(req.WithContext(ctx))
+			c.Response().Header().Set(HeaderRequestID, requestID)
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+			// 後続のミドルウェアはこのcontextから派生したcontextを設定するため、リクエストIDも引き継がれる
+			ctx = c.Request().Context()
+
+			res := c.Response()
+			level := slog.LevelInfo
+			switch {
+			case res.Status >= 500:
+				level = slog.LevelError
+			case res.Status >= 400:
+				level = slog.LevelWarn
+			case quietRoutes[c.Path()]:
+				level = slog.LevelDebug
+			}
+			logger.LogAttrs(ctx, level, "access",
+				slog.String("method", req.Method),
+				slog.String("route", c.Path()),
+				slog.String("path", req.URL.Path),
+				slog.Int("status", res.Status),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.Int64("bytes_out", res.Size),
+				slog.String("remote_ip", c.RealIP()),
+				slog.String("user_agent", req.UserAgent()),
+			)
+			return nil
+		}
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// 乱数が取得できない環境ではリクエストIDなしで処理を続ける
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
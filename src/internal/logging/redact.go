@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 伏せた値の表記
+const redacted = "[REDACTED]"
+
+// 位置情報は小数第2位（約1km）に丸めて出力する
+const coordinatePrecision = 100
+
+var (
+	// 値をすべて伏せる項目（キーの小文字表記）
+	secretKeys = map[string]bool{
+		"authorization": true,
+		"password":      true,
+		"secret":        true,
+		"api_key":       true,
+		"token":         true,
+		"id_token":      true,
+	}
+	// 同じユーザーのログを追えるよう、ハッシュ値に置き換える項目
+	firebaseIDKeys = map[string]bool{
+		"firebase_id":  true,
+		"firebase_uid": true,
+		"uid":          true,
+	}
+	coordinateKeys = map[string]bool{
+		"latitude":  true,
+		"longitude": true,
+		"lat":       true,
+		"lng":       true,
+	}
+
+	// 文字列中のBearerトークンとJWT（エラーメッセージなどに含まれる場合）
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/=-]+`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+)
+
+// Redact はslog.HandlerOptions.ReplaceAttrに設定し、出力前に機密情報を伏せる
+//   - トークン・パスワードなど: [REDACTED]
+//   - Firebase ID: ハッシュ値の先頭（fid:xxxxxxxxxxxx）
+//   - 緯度・経度: 小数第2位に丸める
+//   - その他の文字列・エラー: 含まれるBearerトークンとJWTを[REDACTED]に置き換える
+//
+// 構造体は中身を伏せられないため、ログには必要な項目だけを個別に渡すこと
+func Redact(groups []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	switch {
+	case secretKeys[key] || strings.HasSuffix(key, "_token"):
+		return slog.String(a.Key, redacted)
+	case firebaseIDKeys[key]:
+		return slog.String(a.Key, PseudonymizeID(a.Value.String()))
+	case coordinateKeys[key]:
+		return redactCoordinate(a)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, RedactString(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, RedactString(err.Error()))
+		}
+	}
+	return a
+}
+
+// RedactString は文字列に含まれるBearerトークンとJWTを伏せる
+func RedactString(s string) string {
+	s = bearerPattern.ReplaceAllString(s, "Bearer "+redacted)
+	return jwtPattern.ReplaceAllString(s, redacted)
+}
+
+// PseudonymizeID はFirebase IDなどをログ用のハッシュ値に置き換える
+// 元の値には戻せないが、同じIDは同じ値になるためログの突き合わせに使える
+func PseudonymizeID(id string) string {
+	if id == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(id))
+	return "fid:" + hex.EncodeToString(sum[:6])
+}
+
+func redactCoordinate(a slog.Attr) slog.Attr {
+	var value float64
+	switch a.Value.Kind() {
+	case slog.KindFloat64:
+		value = a.Value.Float64()
+	case slog.KindInt64:
+		return a
+	default:
+		// 文字列やDecimalなど
+		parsed, err := strconv.ParseFloat(a.Value.String(), 64)
+		if err != nil {
+			return slog.String(a.Key, redacted)
+		}
+		value = parsed
+	}
+	return slog.Float64(a.Key, math.Round(value*coordinatePrecision)/coordinatePrecision)
+}
@@ -0,0 +1,161 @@
+// Package metrics はPrometheus形式のメトリクスを提供する（/metricsで公開する）
+//
+// 各パッケージはここで定義したコレクターを直接更新する
+// キャッシュのヒット率などの比率はPromQLで計算する
+//
+//	sum(rate(seicheese_auth_token_cache_lookups_total{result="hit"}[5m])) / sum(rate(seicheese_auth_token_cache_lookups_total[5m]))
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "seicheese"
+
+// HTTP
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests by route and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// リクエスト数の制限
+var (
+	// RateLimited は上限を超えて拒否したリクエストの件数
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Number of requests rejected by the rate limiter by route.",
+	}, []string{"route"})
+)
+
+// 認証
+var (
+	tokenCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_token_cache_lookups_total",
+		Help:      "Number of verified ID token cache lookups by result (hit or miss).",
+	}, []string{"result"})
+)
+
+// ジオコーディング
+// Geocoding APIの結果はキャッシュしていないため、キャッシュのヒット率は記録しない
+// （キャッシュを導入する場合は、トークンのキャッシュと同じくhit・missの件数を記録する）
+var (
+	geocoderRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "geocoder_requests_total",
+		Help:      "Number of Google Maps Geocoding API calls by method and result (ok or error).",
+	}, []string{"method", "result"})
+)
+
+// 業務上の件数
+var (
+	// CheckinsCreated はチェックインの件数
+	CheckinsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "checkins_created_total",
+		Help:      "Number of check-ins.",
+	})
+
+	// SeichiesRegistered は聖地の登録件数
+	SeichiesRegistered = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "seichies_registered_total",
+		Help:      "Number of registered seichies.",
+	})
+
+	// SignUps は新規に作成されたユーザーの件数
+	SignUps = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "signups_total",
+		Help:      "Number of users created by sign-up or user registration.",
+	})
+)
+
+// NewRegistry はアプリケーションのメトリクス・Goランタイム・プロセス・DBコネクションプール（db.Stats()）を登録したレジストリを作成
+// dbがnilの場合はコネクションプールのメトリクスを含めない
+func NewRegistry(db *sql.DB) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		RateLimited,
+		tokenCacheLookups,
+		geocoderRequests,
+		CheckinsCreated,
+		SeichiesRegistered,
+		SignUps,
+	)
+	if db != nil {
+		registry.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+	}
+	return registry
+}
+
+// Handler はregistryのメトリクスをPrometheusのテキスト形式で返すハンドラ
+func Handler(registry *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// Middleware はルート（/api/users/:id などの登録時のパス）とステータスごとにリクエスト数と処理時間を記録する
+// エラーはここでHTTPErrorHandlerに渡し、確定したステータスを記録する
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			labels := prometheus.Labels{
+				"method": c.Request().Method,
+				"route":  c.Path(),
+				"status": strconv.Itoa(c.Response().Status),
+			}
+			httpRequests.With(labels).Inc()
+			httpDuration.With(labels).Observe(time.Since(start).Seconds())
+			return nil
+		}
+	}
+}
+
+// ObserveTokenCache は検証済みトークンのキャッシュの参照結果を記録する
+func ObserveTokenCache(hit bool) {
+	tokenCacheLookups.WithLabelValues(cacheResult(hit)).Inc()
+}
+
+// ObserveGeocoderRequest はGeocoding APIの呼び出し結果を記録する
+func ObserveGeocoderRequest(method string, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	geocoderRequests.WithLabelValues(method, result).Inc()
+}
+
+func cacheResult(hit bool) string {
+	if hit {
+		return "hit"
+	}
+	return "miss"
+}
@@ -0,0 +1,69 @@
+// Seicheese-Backend/src/internal/middleware/appversion.go
+
+package middleware
+
+import (
+	"seicheese/internal/apperror"
+	"seicheese/internal/appversion"
+
+	"github.com/labstack/echo/v4"
+)
+
+// バージョンを確認しないルート（ロードバランサーや監視から呼ばれる）
+var appVersionExemptPaths = map[string]bool{
+	"/health":  true,
+	"/livez":   true,
+	"/readyz":  true,
+	"/version": true,
+	"/metrics": true,
+}
+
+// AppVersionMiddleware はX-App-Versionヘッダーのアプリのバージョンをpolicyで確認する
+// 最低サポートバージョンより古い場合はUPGRADE_REQUIREDを返し、推奨バージョンより古い場合はX-App-Recommended-Versionヘッダーを付ける
+// ヘッダーのないリクエストはpolicyでヘッダーを必須にしている場合だけ拒否する
+func AppVersionMiddleware(policy *appversion.Policy) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if appVersionExemptPaths[c.Path()] {
+				return next(c)
+			}
+
+			version := c.Request().Header.Get(appversion.HeaderAppVersion)
+			if version == "" {
+				if policy.RequireHeader() {
+					return apperror.ErrVersionRequired
+				}
+				return next(c)
+			}
+
+			if err := CheckAppVersion(c, policy, version); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// CheckAppVersion はアプリのバージョンがサポート対象か確認する
+// プラットフォームはX-App-Platformヘッダーから決める
+func CheckAppVersion(c echo.Context, policy *appversion.Policy, version string) error {
+	if version == "" {
+		return apperror.ErrVersionRequired
+	}
+
+	result, err := policy.Check(c.Request().Header.Get(appversion.HeaderAppPlatform), version)
+	if err != nil {
+		return apperror.ErrUnsupportedVersion.Wrap(err)
+	}
+
+	switch result.Status {
+	case appversion.UpgradeRequired:
+		return apperror.ErrUpgradeRequired.WithDetails(map[string]interface{}{
+			"min_version": result.MinSupported,
+			"store_urls":  result.StoreURLs,
+		})
+	case appversion.UpdateRecommended:
+		c.Response().Header().Set(appversion.HeaderRecommendedVersion, result.Recommended)
+	}
+	return nil
+}
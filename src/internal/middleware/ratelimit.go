@@ -0,0 +1,44 @@
+// Seicheese-Backend/src/internal/middleware/ratelimit.go
+
+package middleware
+
+import (
+	"log/slog"
+	"strconv"
+
+	"seicheese/internal/apperror"
+	"seicheese/internal/metrics"
+	"seicheese/internal/ratelimit"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimitMiddleware はルートごとの上限を超えたリクエストを429で拒否し、Retry-Afterヘッダーで再試行までの秒数を返す
+// 利用者はFirebase UIDで区別するため、FirebaseAuthMiddlewareの後に適用すること（UIDがない場合はIPアドレスで区別する）
+// limiterがnilの場合は制限しない
+func RateLimitMiddleware(limiter *ratelimit.Limiter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			route := c.Request().Method + " " + c.Path()
+			subject := "ip:" + c.RealIP()
+			if uid, ok := UIDFromContext(c); ok {
+				subject = "uid:" + uid
+			}
+
+			ctx := c.Request().Context()
+			allowed, retryAfter, err := limiter.Allow(ctx, route, subject)
+			if err != nil {
+				// 共有ストアの障害でサービス全体を止めないよう、制限せずに通す
+				slog.WarnContext(ctx, "rate limit store error", "route", route, "error", err)
+				return next(c)
+			}
+			if !allowed {
+				seconds := ratelimit.RetryAfterSeconds(retryAfter)
+				metrics.RateLimited.WithLabelValues(c.Path()).Inc()
+				c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(seconds))
+				return apperror.ErrTooManyRequests.WithDetails(map[string]interface{}{"retry_after": seconds})
+			}
+			return next(c)
+		}
+	}
+}
@@ -3,27 +3,24 @@
 package router
 
 import (
-	"net/http"
+	"seicheese/internal/auth"
 	"seicheese/internal/handler"
 	"seicheese/internal/middleware"
+	"seicheese/internal/ratelimit"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterAuthRoutes(e *echo.Echo, authClient *auth.Client, authHandler *handler.AuthHandler) {
-	// ヘルスチェックエンドポイントを追加（認証不要）
-	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(http.StatusOK, map[string]string{
-			"status": "ok",
-		})
-	})
-
+func RegisterAuthRoutes(e *echo.Echo, verifier auth.TokenVerifier, authConfig *auth.Config, authHandler *handler.AuthHandler, limiter *ratelimit.Limiter) {
 	// バックエンドでのトークンとバージョンの検証エンドポイントを追加
-	e.POST("/auth/validate", authHandler.ValidateToken)
+	// 無効なトークンでの試行も数えるよう、トークンの検証より前にIPアドレスで制限する
+	e.POST("/auth/validate", authHandler.ValidateToken,
+		middleware.RateLimitMiddleware(limiter),
+		middleware.FirebaseAuthMiddleware(verifier, authConfig))
 
 	authGroup := e.Group("")
-	authGroup.Use(middleware.FirebaseAuthMiddleware(authClient))
+	authGroup.Use(middleware.FirebaseAuthMiddleware(verifier, authConfig))
+	authGroup.Use(middleware.RateLimitMiddleware(limiter))
 	// ハンドラーの割り当て
 	authGroup.POST("/auth/signin", authHandler.SignIn)
 	authGroup.POST("/auth/signup", authHandler.SignUp)
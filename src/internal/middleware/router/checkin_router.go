@@ -1,19 +1,23 @@
 package router
 
 import (
+	"seicheese/internal/auth"
 	"seicheese/internal/handler"
 	"seicheese/internal/middleware"
+	"seicheese/internal/ratelimit"
+	"seicheese/internal/repository"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterCheckinRoutes(e *echo.Echo, checkinHandler *handler.CheckinHandler, authClient *auth.Client) {
+func RegisterCheckinRoutes(e *echo.Echo, checkinHandler *handler.CheckinHandler, verifier auth.TokenVerifier, authConfig *auth.Config, users repository.UserRepository, limiter *ratelimit.Limiter) {
 	// チェックイン関連のルーティンググループ
 	checkinGroup := e.Group("/api/checkins")
 
-	// すべてのエンドポイントで認証が必要
-	checkinGroup.Use(middleware.FirebaseAuthMiddleware(authClient))
+	// すべてのエンドポイントで認証と登録済みユーザーが必要
+	checkinGroup.Use(middleware.FirebaseAuthMiddleware(verifier, authConfig))
+	checkinGroup.Use(middleware.RateLimitMiddleware(limiter))
+	checkinGroup.Use(middleware.LoadUserMiddleware(users))
 
 	// チェックイン履歴の取得
 	checkinGroup.GET("", checkinHandler.GetUserCheckins)
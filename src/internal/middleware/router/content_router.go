@@ -2,16 +2,18 @@
 package router
 
 import (
+	"seicheese/internal/auth"
 	"seicheese/internal/handler"
 	"seicheese/internal/middleware"
+	"seicheese/internal/ratelimit"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterContentRoutes(e *echo.Echo, contentHandler *handler.ContentHandler, authClient *auth.Client) {
+func RegisterContentRoutes(e *echo.Echo, contentHandler *handler.ContentHandler, verifier auth.TokenVerifier, authConfig *auth.Config, limiter *ratelimit.Limiter) {
 	contentGroup := e.Group("/api/contents")
-	contentGroup.Use(middleware.FirebaseAuthMiddleware(authClient))
+	contentGroup.Use(middleware.FirebaseAuthMiddleware(verifier, authConfig))
+	contentGroup.Use(middleware.RateLimitMiddleware(limiter))
 
 	contentGroup.GET("/search", contentHandler.SearchContents)
 	contentGroup.POST("/register", contentHandler.RegisterContent)
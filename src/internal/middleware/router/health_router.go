@@ -0,0 +1,16 @@
+package router
+
+import (
+	"seicheese/internal/handler"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterHealthRoutes は監視用のエンドポイントを登録する（認証不要）
+func RegisterHealthRoutes(e *echo.Echo, healthHandler *handler.HealthHandler) {
+	// /healthは従来のクライアント向けに/livezと同じ結果を返す
+	e.GET("/health", healthHandler.Livez)
+	e.GET("/livez", healthHandler.Livez)
+	e.GET("/readyz", healthHandler.Readyz)
+	e.GET("/version", healthHandler.Version)
+}
@@ -0,0 +1,12 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterMetricsRoutes はPrometheusが収集するメトリクスのエンドポイントを登録する（認証不要）
+func RegisterMetricsRoutes(e *echo.Echo, metricsHandler http.Handler) {
+	e.GET("/metrics", echo.WrapHandler(metricsHandler))
+}
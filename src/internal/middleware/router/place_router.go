@@ -1,16 +1,18 @@
 package router
 
 import (
+	"seicheese/internal/auth"
 	"seicheese/internal/handler"
 	"seicheese/internal/middleware"
+	"seicheese/internal/ratelimit"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterPlaceRoutes(e *echo.Echo, placeHandler *handler.PlaceHandler, authClient *auth.Client) {
+func RegisterPlaceRoutes(e *echo.Echo, placeHandler *handler.PlaceHandler, verifier auth.TokenVerifier, authConfig *auth.Config, limiter *ratelimit.Limiter) {
 	placeGroup := e.Group("/api/places")
-	placeGroup.Use(middleware.FirebaseAuthMiddleware(authClient))
+	placeGroup.Use(middleware.FirebaseAuthMiddleware(verifier, authConfig))
+	placeGroup.Use(middleware.RateLimitMiddleware(limiter))
 
 	placeGroup.GET("", placeHandler.GetPlace)
 	placeGroup.POST("", placeHandler.RegisterPlace)
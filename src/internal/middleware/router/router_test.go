@@ -0,0 +1,920 @@
+package router_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"seicheese/internal/apperror"
+	"seicheese/internal/appversion"
+	authpkg "seicheese/internal/auth"
+	"seicheese/internal/buildinfo"
+	"seicheese/internal/handler"
+	"seicheese/internal/i18n"
+	"seicheese/internal/logging"
+	"seicheese/internal/metrics"
+	"seicheese/internal/middleware"
+	"seicheese/internal/middleware/router"
+	"seicheese/internal/ratelimit"
+	"seicheese/internal/repository"
+	"seicheese/internal/security"
+	"seicheese/internal/validation"
+	"seicheese/models"
+	"seicheese/services"
+
+	firebaseauth "firebase.google.com/go/v4/auth"
+	"github.com/ericlagergren/decimal"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// go test ./internal/middleware/router -update でゴールデンファイルを更新する
+var update = flag.Bool("update", false, "update golden files")
+
+const (
+	testProjectID = "seicheese-test"
+
+	// 登録済みユーザーと未登録ユーザーのトークン
+	registeredToken   = "token-registered"
+	unregisteredToken = "token-unregistered"
+	registeredUID     = "uid-registered"
+	unregisteredUID   = "uid-unregistered"
+)
+
+// testVersions は0.1.0以上をサポートし、1.0.0へのアップデートを推奨する方針
+var testVersions = mustPolicy(&appversion.Config{
+	MinSupported: "0.1.0",
+	Recommended:  "1.0.0",
+	IOS:          appversion.PlatformConfig{StoreURL: "https://apps.apple.com/jp/app/id0000000000"},
+	Android:      appversion.PlatformConfig{MinSupported: "0.2.0", StoreURL: "https://play.google.com/store/apps/details?id=jp.seicheese"},
+})
+
+func mustPolicy(config *appversion.Config) *appversion.Policy {
+	policy, err := appversion.NewPolicy(config)
+	if err != nil {
+		panic(err)
+	}
+	return policy
+}
+
+// fakeAuth はトークン文字列とUIDの対応表で検証するauth.Provider
+type fakeAuth struct {
+	mu      sync.Mutex
+	uids    map[string]string
+	deleted []string
+}
+
+func (a *fakeAuth) VerifyIDToken(ctx context.Context, idToken string) (*firebaseauth.Token, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	uid, ok := a.uids[idToken]
+	if !ok {
+		return nil, errors.New("unknown token")
+	}
+	now := time.Now()
+	return &firebaseauth.Token{
+		Issuer:   "https://securetoken.google.com/" + testProjectID,
+		Audience: testProjectID,
+		Expires:  now.Add(time.Hour).Unix(),
+		IssuedAt: now.Add(-time.Minute).Unix(),
+		Subject:  uid,
+		UID:      uid,
+		Claims:   map[string]interface{}{"user_id": uid},
+	}, nil
+}
+
+func (a *fakeAuth) DeleteUser(ctx context.Context, uid string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.deleted = append(a.deleted, uid)
+	return nil
+}
+
+// fakeBlobStore はメモリ上に保存するstorage.BlobStore
+type fakeBlobStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+	// 保存先のURL（nilの場合はhttps://storage.example.com/<key>）
+	url func(key string) string
+}
+
+func (s *fakeBlobStore) Put(ctx context.Context, key, contentType string, r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[key] = data
+	if s.url != nil {
+		return s.url(key), nil
+	}
+	return "https://storage.example.com/" + key, nil
+}
+
+func (s *fakeBlobStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.blobs, key)
+	return nil
+}
+
+// fakeAddresses は座標ごとの住所（フィクスチャの場所と、まだ登録されていない場所）
+var fakeAddresses = map[[2]float64]string{
+	{35.6851, 139.7224}: "東京都新宿区須賀町5",
+	{35.6856, 139.7230}: "東京都新宿区須賀町8",
+}
+
+// fakeGeocoder はfakeAddressesの住所を返すservices.Geocoder（それ以外の座標は住所のない位置として扱う）
+// GetFormattedAddress は座標によらず同じ住所を返す
+type fakeGeocoder struct{}
+
+func (fakeGeocoder) GetAddressFromLatLng(ctx context.Context, lat, lng float64) (map[string]string, error) {
+	address, ok := fakeAddresses[[2]float64{lat, lng}]
+	if !ok {
+		return nil, services.ErrNoAddress
+	}
+	return map[string]string{
+		"address":    address,
+		"postalCode": "160-0018",
+	}, nil
+}
+
+func (fakeGeocoder) GetFormattedAddress(ctx context.Context, lat, lng float64) (map[string]string, error) {
+	return map[string]string{
+		"address":    "日本、〒160-0018 東京都新宿区須賀町5",
+		"postalCode": "160-0018",
+	}, nil
+}
+
+type testServer struct {
+	echo   *echo.Echo
+	store  *repository.MemoryStore
+	auth   *fakeAuth
+	blobs  *fakeBlobStore
+	health *handler.HealthHandler
+	// 既定ではどのルートも制限しない（テストごとにRoutesを設定する）
+	limiter *ratelimit.Limiter
+}
+
+// newTestServer はフィクスチャを投入したメモリ上のリポジトリで、routerパッケージの全ルートを登録する
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	store := repository.NewMemoryStore()
+	seedFixtures(store)
+	repos := repository.NewMemoryRepositories(store)
+
+	auth := &fakeAuth{uids: map[string]string{
+		registeredToken:   registeredUID,
+		unregisteredToken: unregisteredUID,
+	}}
+	blobs := &fakeBlobStore{blobs: map[string][]byte{}}
+	authConfig := &authpkg.Config{Mode: authpkg.ModeFirebase, ProjectID: testProjectID}
+
+	e := echo.New()
+	e.HTTPErrorHandler = apperror.HTTPErrorHandler
+	e.IPExtractor = security.IPExtractor(&security.Config{})
+	e.Use(logging.Middleware(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+	e.Use(metrics.Middleware())
+	e.Use(i18n.Middleware())
+	e.Use(middleware.AppVersionMiddleware(testVersions))
+	e.Validator = validation.New(repos.ExistsCheckers())
+
+	userService := &services.UserService{Users: repos.Users}
+
+	health := &handler.HealthHandler{
+		Checks: []handler.ReadinessCheck{
+			{Name: "database", Check: func(ctx context.Context) error { return nil }},
+		},
+		BuildInfo: buildinfo.Info{Version: "1.2.0", Commit: "0123456789abcdef", BuildTime: "2024-11-01T09:00:00Z"},
+	}
+	router.RegisterHealthRoutes(e, health)
+	router.RegisterMetricsRoutes(e, metrics.Handler(metrics.NewRegistry(nil)))
+
+	limiter := &ratelimit.Limiter{Store: ratelimit.NewMemoryStore(), Routes: map[string]ratelimit.Limit{}}
+
+	router.RegisterAuthRoutes(e, auth, authConfig, &handler.AuthHandler{
+		Users:       repos.Users,
+		UserService: userService,
+		Versions:    testVersions,
+	}, limiter)
+	router.RegisterGenreRoutes(e, &handler.GenreHandler{Genres: repos.Genres})
+	router.RegisterSeichiRoutes(e, &handler.SeichiHandler{
+		Seichies: repos.Seichies,
+		Geocoder: fakeGeocoder{},
+	}, auth, authConfig, repos.Users, limiter)
+	router.RegisterContentRoutes(e, &handler.ContentHandler{Contents: repos.Contents}, auth, authConfig, limiter)
+	router.RegisterPlaceRoutes(e, &handler.PlaceHandler{
+		Places:   repos.Places,
+		Geocoder: fakeGeocoder{},
+	}, auth, authConfig, limiter)
+	router.RegisterCheckinRoutes(e, &handler.CheckinHandler{Checkins: repos.Checkins}, auth, authConfig, repos.Users, limiter)
+	router.RegisterUserRoutes(e, &handler.UserHandler{
+		Users:       repos.Users,
+		UserService: userService,
+		Genres:      repos.Genres,
+		Seichies:    repos.Seichies,
+		Checkins:    repos.Checkins,
+		Storage:     blobs,
+		AuthUsers:   auth,
+	}, auth, authConfig, repos.Users, limiter)
+
+	return &testServer{echo: e, store: store, auth: auth, blobs: blobs, health: health, limiter: limiter}
+}
+
+// seedFixtures は各テストで共通のデータを投入する（IDは投入順に1から採番される）
+func seedFixtures(store *repository.MemoryStore) {
+	createdAt := time.Date(2024, 11, 1, 9, 0, 0, 0, time.UTC)
+
+	genre := store.AddGenre(&models.Genre{
+		GenreName:   "アニメ",
+		GenreNameEn: null.StringFrom("Anime"),
+		CreatedAt:   null.TimeFrom(createdAt),
+		UpdatedAt:   null.TimeFrom(createdAt),
+	})
+	content := store.AddContent(&models.Content{
+		ContentName:   "君の名は。",
+		ContentNameEn: null.StringFrom("Your Name."),
+		GenreID:       genre.GenreID,
+		CreatedAt:     null.TimeFrom(createdAt),
+		UpdatedAt:     null.TimeFrom(createdAt),
+	})
+	place := store.AddPlace(&models.Place{
+		Address:   "東京都新宿区須賀町5",
+		ZipCode:   "160-0018",
+		CreatedAt: null.TimeFrom(createdAt),
+		UpdatedAt: null.TimeFrom(createdAt),
+	})
+	user := store.AddUser(&models.User{
+		FirebaseID:       registeredUID,
+		DisplayName:      null.StringFrom("聖地太郎"),
+		Bio:              null.StringFrom("聖地巡礼が趣味です"),
+		FavoriteGenreIds: null.JSONFrom([]byte("[1]")),
+		CreatedAt:        null.TimeFrom(createdAt),
+		UpdatedAt:        null.TimeFrom(createdAt),
+	})
+	seichi := store.AddSeichi(&models.Seichy{
+		UserID:     null.UintFrom(user.UserID),
+		SeichiName: "須賀神社",
+		Comment:    null.StringFrom("ラストシーンの階段"),
+		Latitude:   types.NewDecimal(decimal.New(356851, 4)),
+		Longitude:  types.NewDecimal(decimal.New(1397224, 4)),
+		PlaceID:    place.PlaceID,
+		ContentID:  content.ContentID,
+		CreatedAt:  null.TimeFrom(createdAt),
+		UpdatedAt:  null.TimeFrom(createdAt),
+	})
+	store.AddCheckin(&models.CheckinLog{
+		UserID:    user.UserID,
+		SeichiID:  seichi.SeichiID,
+		CreatedAt: createdAt.Add(time.Hour),
+	})
+	store.SetPoint(&models.Point{
+		UserID:       user.UserID,
+		CurrentPoint: 10,
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt.Add(time.Hour),
+	})
+	store.AddPointLog(&models.PointLog{
+		UserID:    user.UserID,
+		Point:     10,
+		CreatedAt: createdAt.Add(time.Hour),
+	})
+}
+
+type routeTest struct {
+	name   string
+	method string
+	// route はechoに登録されたパス（全ルートの網羅確認に使う）
+	route string
+	// path は実際にリクエストするパス（空の場合はrouteと同じ）
+	path   string
+	token  string
+	lang   string
+	body   string
+	avatar []byte
+	status int
+	// contains はゴールデンファイルの代わりにレスポンスに含まれることを確認する文字列（テキスト形式のレスポンス用）
+	contains string
+}
+
+// PNGのシグネチャ（http.DetectContentTypeがimage/pngと判定する最小のデータ）
+var pngData = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+var routeTests = []routeTest{
+	// ヘルスチェック・ビルド情報
+	{name: "health", method: http.MethodGet, route: "/health", status: http.StatusOK},
+	{name: "livez", method: http.MethodGet, route: "/livez", status: http.StatusOK},
+	{name: "readyz", method: http.MethodGet, route: "/readyz", status: http.StatusOK},
+	{name: "version", method: http.MethodGet, route: "/version", status: http.StatusOK},
+	{name: "metrics", method: http.MethodGet, route: "/metrics", status: http.StatusOK, contains: "# TYPE seicheese_http_requests_total counter"},
+
+	// 認証
+	{name: "auth_validate", method: http.MethodPost, route: "/auth/validate", token: registeredToken, body: `{"version":"1.0.0"}`, status: http.StatusOK},
+	{name: "auth_validate_en", method: http.MethodPost, route: "/auth/validate", token: registeredToken, lang: "en", body: `{"version":"1.0.0"}`, status: http.StatusOK},
+	{name: "auth_validate_upgrade_required", method: http.MethodPost, route: "/auth/validate", token: registeredToken, body: `{"version":"0.0.9"}`, status: http.StatusUpgradeRequired},
+	{name: "auth_validate_upgrade_required_en", method: http.MethodPost, route: "/auth/validate", token: registeredToken, lang: "en", body: `{"version":"0.0.9"}`, status: http.StatusUpgradeRequired},
+	{name: "auth_validate_unsupported_version", method: http.MethodPost, route: "/auth/validate", token: registeredToken, body: `{"version":"1.0"}`, status: http.StatusBadRequest},
+	{name: "auth_validate_token_required", method: http.MethodPost, route: "/auth/validate", body: `{"version":"1.0.0"}`, status: http.StatusUnauthorized},
+	{name: "auth_signin", method: http.MethodPost, route: "/auth/signin", token: registeredToken, status: http.StatusOK},
+	{name: "auth_signin_not_found", method: http.MethodPost, route: "/auth/signin", token: unregisteredToken, status: http.StatusNotFound},
+	{name: "auth_signin_invalid_token", method: http.MethodPost, route: "/auth/signin", token: "token-unknown", status: http.StatusUnauthorized},
+	{name: "auth_signup", method: http.MethodPost, route: "/auth/signup", token: unregisteredToken, body: `{"version":"1.0.0"}`, status: http.StatusCreated},
+	{name: "auth_signup_exists", method: http.MethodPost, route: "/auth/signup", token: registeredToken, body: `{"version":"1.0.0"}`, status: http.StatusConflict},
+
+	// ジャンル
+	{name: "genres", method: http.MethodGet, route: "/api/genres", status: http.StatusOK},
+	{name: "genres_en", method: http.MethodGet, route: "/api/genres", lang: "en", status: http.StatusOK},
+
+	// 聖地
+	{name: "seichi_list", method: http.MethodGet, route: "/api/seichi/list", token: registeredToken, status: http.StatusOK},
+	{name: "seichi_register", method: http.MethodPost, route: "/api/seichi/register", token: registeredToken,
+		body: `{"seichi_name":"新宿区立須賀公園","comment":"","latitude":35.6856,"longitude":139.7230,"content_id":2}`, status: http.StatusCreated},
+	{name: "seichi_register_validation_failed", method: http.MethodPost, route: "/api/seichi/register", token: registeredToken,
+		body: `{"seichi_name":"","latitude":10.0,"longitude":139.7230,"content_id":999}`, status: http.StatusBadRequest},
+	{name: "seichi_register_not_registered", method: http.MethodPost, route: "/api/seichi/register", token: unregisteredToken,
+		body: `{"seichi_name":"須賀神社","latitude":35.6851,"longitude":139.7224,"content_id":2}`, status: http.StatusForbidden},
+
+	// 作品
+	{name: "contents_search", method: http.MethodGet, route: "/api/contents/search", path: "/api/contents/search?q=your", token: registeredToken, status: http.StatusOK},
+	{name: "contents_search_empty", method: http.MethodGet, route: "/api/contents/search", token: registeredToken, status: http.StatusOK},
+	{name: "contents_register", method: http.MethodPost, route: "/api/contents/register", token: registeredToken,
+		body: `{"content_name":"天気の子","content_name_en":"Weathering with You","genre_id":1}`, status: http.StatusCreated},
+	{name: "contents_register_genre_not_found", method: http.MethodPost, route: "/api/contents/register", token: registeredToken,
+		body: `{"content_name":"天気の子","genre_id":999}`, status: http.StatusBadRequest},
+
+	// 場所
+	{name: "places", method: http.MethodGet, route: "/api/places", token: registeredToken, status: http.StatusOK},
+	{name: "places_register", method: http.MethodPost, route: "/api/places", token: registeredToken,
+		body: `{"latitude":35.6856,"longitude":139.7230}`, status: http.StatusCreated},
+	{name: "places_register_existing", method: http.MethodPost, route: "/api/places", token: registeredToken,
+		body: `{"latitude":35.6851,"longitude":139.7224}`, status: http.StatusCreated},
+	{name: "places_register_address_not_found", method: http.MethodPost, route: "/api/places", token: registeredToken,
+		body: `{"latitude":30.0,"longitude":135.0}`, status: http.StatusBadRequest},
+
+	// チェックイン
+	{name: "checkins", method: http.MethodGet, route: "/api/checkins", token: registeredToken, status: http.StatusOK},
+	{name: "checkins_create", method: http.MethodPost, route: "/api/checkins", token: registeredToken, body: `{"seichi_id":5}`, status: http.StatusOK},
+	{name: "checkins_create_seichi_not_found", method: http.MethodPost, route: "/api/checkins", token: registeredToken, body: `{"seichi_id":999}`, status: http.StatusBadRequest},
+
+	// ユーザー
+	{name: "users_register", method: http.MethodPost, route: "/api/users", token: unregisteredToken, body: `{"name":"聖地花子"}`, status: http.StatusCreated},
+	{name: "users_register_exists", method: http.MethodPost, route: "/api/users", token: registeredToken, body: `{"name":"聖地太郎"}`, status: http.StatusConflict},
+	{name: "users_me", method: http.MethodGet, route: "/api/users/me", token: registeredToken, status: http.StatusOK},
+	{name: "users_me_not_registered", method: http.MethodGet, route: "/api/users/me", token: unregisteredToken, status: http.StatusForbidden},
+	{name: "users_me_update", method: http.MethodPatch, route: "/api/users/me", token: registeredToken,
+		body: `{"name":"聖地次郎","bio":"","favorite_genre_ids":[1,1]}`, status: http.StatusOK},
+	{name: "users_me_update_genre_not_found", method: http.MethodPatch, route: "/api/users/me", token: registeredToken,
+		body: `{"favorite_genre_ids":[999]}`, status: http.StatusBadRequest},
+	{name: "users_me_delete", method: http.MethodDelete, route: "/api/users/me", path: "/api/users/me?delete_firebase_user=true", token: registeredToken, status: http.StatusOK},
+	{name: "users_me_export", method: http.MethodGet, route: "/api/users/me/export", path: "/api/users/me/export?format=json", token: registeredToken, status: http.StatusOK},
+	{name: "users_me_avatar_upload", method: http.MethodPut, route: "/api/users/me/avatar", token: registeredToken, avatar: pngData, status: http.StatusOK},
+	{name: "users_me_avatar_unsupported_type", method: http.MethodPut, route: "/api/users/me/avatar", token: registeredToken, avatar: []byte("plain text"), status: http.StatusUnsupportedMediaType},
+	{name: "users_me_avatar_delete", method: http.MethodDelete, route: "/api/users/me/avatar", token: registeredToken, status: http.StatusOK},
+	{name: "users_public_profile", method: http.MethodGet, route: "/api/users/:id", path: "/api/users/4", token: registeredToken, status: http.StatusOK},
+	{name: "users_public_profile_not_found", method: http.MethodGet, route: "/api/users/:id", path: "/api/users/999", token: registeredToken, status: http.StatusNotFound},
+	{name: "users_public_profile_invalid_id", method: http.MethodGet, route: "/api/users/:id", path: "/api/users/abc", token: registeredToken, status: http.StatusBadRequest},
+}
+
+func TestRoutes(t *testing.T) {
+	for _, tt := range routeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(t)
+
+			rec := httptest.NewRecorder()
+			srv.echo.ServeHTTP(rec, newRequest(t, tt))
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d\nbody: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.contains != "" {
+				if !strings.Contains(rec.Body.String(), tt.contains) {
+					t.Errorf("body does not contain %q\nbody: %s", tt.contains, rec.Body.String())
+				}
+				return
+			}
+			assertGolden(t, tt.name, rec.Body.Bytes())
+		})
+	}
+}
+
+// routerパッケージで登録されたすべてのルートにテストケースがあることを確認する
+func TestRoutesCovered(t *testing.T) {
+	srv := newTestServer(t)
+
+	tested := map[string]bool{}
+	for _, tt := range routeTests {
+		tested[tt.method+" "+tt.route] = true
+	}
+
+	var missing []string
+	for _, r := range srv.echo.Routes() {
+		if r.Method == echo.RouteNotFound {
+			continue
+		}
+		if key := r.Method + " " + r.Path; !tested[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		t.Errorf("routes without tests:\n%s", strings.Join(missing, "\n"))
+	}
+}
+
+// 副作用が保存されていることを確認する
+func TestDeleteAccountSideEffects(t *testing.T) {
+	srv := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	srv.echo.ServeHTTP(rec, newRequest(t, routeTest{
+		method: http.MethodDelete,
+		path:   "/api/users/me?delete_firebase_user=true",
+		token:  registeredToken,
+	}))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d\nbody: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	if len(srv.auth.deleted) != 1 || srv.auth.deleted[0] != registeredUID {
+		t.Errorf("deleted firebase users = %v, want [%s]", srv.auth.deleted, registeredUID)
+	}
+
+	// 削除後は未登録ユーザーとして扱われる
+	rec = httptest.NewRecorder()
+	srv.echo.ServeHTTP(rec, newRequest(t, routeTest{
+		method: http.MethodGet,
+		path:   "/api/users/me",
+		token:  registeredToken,
+	}))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status after delete = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+// 住所の異なる座標は別の場所として登録され、同じ座標は同じ場所になる
+func TestRegisterPlaceByAddress(t *testing.T) {
+	srv := newTestServer(t)
+
+	register := func(body string) int {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		srv.echo.ServeHTTP(rec, newRequest(t, routeTest{
+			method: http.MethodPost,
+			path:   "/api/places",
+			token:  registeredToken,
+			body:   body,
+		}))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d\nbody: %s", rec.Code, http.StatusCreated, rec.Body.String())
+		}
+		var place models.Place
+		if err := json.Unmarshal(rec.Body.Bytes(), &place); err != nil {
+			t.Fatal(err)
+		}
+		if place.Address == "" {
+			t.Errorf("registered place %d with empty address", place.PlaceID)
+		}
+		return place.PlaceID
+	}
+
+	first := register(`{"latitude":35.6856,"longitude":139.7230}`)
+	second := register(`{"latitude":35.6851,"longitude":139.7224}`)
+	if first == second {
+		t.Errorf("different coordinates registered the same place %d", first)
+	}
+	if again := register(`{"latitude":35.6856,"longitude":139.7230}`); again != first {
+		t.Errorf("same coordinates registered place %d, want %d", again, first)
+	}
+}
+
+// すべての認証付きルートで同じ形式のAuthorizationヘッダーを要求する
+func TestAuthorizationHeaderFormat(t *testing.T) {
+	srv := newTestServer(t)
+
+	for _, tt := range []struct {
+		method, path, body string
+	}{
+		{http.MethodPost, "/auth/validate", `{"version":"1.0.0"}`},
+		{http.MethodPost, "/auth/signin", ""},
+		{http.MethodGet, "/api/users/me", ""},
+	} {
+		req := newRequest(t, routeTest{method: tt.method, path: tt.path, body: tt.body})
+		req.Header.Set(echo.HeaderAuthorization, registeredToken)
+		rec := httptest.NewRecorder()
+		srv.echo.ServeHTTP(rec, req)
+
+		var body struct{ Error struct{ Code string } }
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusUnauthorized || body.Error.Code != apperror.ErrInvalidTokenFormat.Code {
+			t.Errorf("%s %s without Bearer = %d %s, want 401 %s", tt.method, tt.path, rec.Code, rec.Body.String(), apperror.ErrInvalidTokenFormat.Code)
+		}
+	}
+}
+
+// X-App-Versionヘッダーはすべてのルートで確認し、ヘルスチェックなどは対象外とする
+func TestAppVersionHeader(t *testing.T) {
+	srv := newTestServer(t)
+
+	tests := []struct {
+		name        string
+		path        string
+		version     string
+		platform    string
+		status      int
+		recommended string
+		storeURLs   map[string]string
+	}{
+		{name: "no header", path: "/api/genres", status: http.StatusOK},
+		{name: "latest", path: "/api/genres", version: "1.0.0", platform: "ios", status: http.StatusOK},
+		{name: "update recommended", path: "/api/genres", version: "0.9.0", platform: "ios", status: http.StatusOK, recommended: "1.0.0"},
+		{name: "ios upgrade required", path: "/api/genres", version: "0.0.9", platform: "ios", status: http.StatusUpgradeRequired,
+			storeURLs: map[string]string{"ios": "https://apps.apple.com/jp/app/id0000000000"}},
+		{name: "android minimum", path: "/api/genres", version: "0.1.5", platform: "android", status: http.StatusUpgradeRequired,
+			storeURLs: map[string]string{"android": "https://play.google.com/store/apps/details?id=jp.seicheese"}},
+		{name: "unknown platform", path: "/api/genres", version: "0.0.9", status: http.StatusUpgradeRequired,
+			storeURLs: map[string]string{"ios": "https://apps.apple.com/jp/app/id0000000000", "android": "https://play.google.com/store/apps/details?id=jp.seicheese"}},
+		{name: "pre-release of minimum", path: "/api/genres", version: "0.1.0-beta.1", status: http.StatusUpgradeRequired,
+			storeURLs: map[string]string{"ios": "https://apps.apple.com/jp/app/id0000000000", "android": "https://play.google.com/store/apps/details?id=jp.seicheese"}},
+		{name: "malformed", path: "/api/genres", version: "1.0", status: http.StatusBadRequest},
+		{name: "health check", path: "/livez", version: "0.0.9", status: http.StatusOK},
+		{name: "legacy health check", path: "/health", version: "0.0.9", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRequest(t, routeTest{method: http.MethodGet, path: tt.path})
+			if tt.version != "" {
+				req.Header.Set(appversion.HeaderAppVersion, tt.version)
+			}
+			if tt.platform != "" {
+				req.Header.Set(appversion.HeaderAppPlatform, tt.platform)
+			}
+			rec := httptest.NewRecorder()
+			srv.echo.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d\nbody: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if got := rec.Header().Get(appversion.HeaderRecommendedVersion); got != tt.recommended {
+				t.Errorf("%s = %q, want %q", appversion.HeaderRecommendedVersion, got, tt.recommended)
+			}
+			if tt.status != http.StatusUpgradeRequired {
+				return
+			}
+			var body struct {
+				Error struct {
+					Code    string
+					Details struct {
+						MinVersion string            `json:"min_version"`
+						StoreURLs  map[string]string `json:"store_urls"`
+					}
+				}
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error.Code != apperror.ErrUpgradeRequired.Code || body.Error.Details.MinVersion == "" {
+				t.Fatalf("body = %s, want UPGRADE_REQUIRED with min_version", rec.Body.String())
+			}
+			if !reflect.DeepEqual(body.Error.Details.StoreURLs, tt.storeURLs) {
+				t.Errorf("store_urls = %v, want %v", body.Error.Details.StoreURLs, tt.storeURLs)
+			}
+		})
+	}
+
+	// ヘッダーを必須にした場合
+	e := echo.New()
+	e.HTTPErrorHandler = apperror.HTTPErrorHandler
+	e.Use(middleware.AppVersionMiddleware(mustPolicy(&appversion.Config{MinSupported: "0.1.0", RequireHeader: true})))
+	e.GET("/api/genres", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+	router.RegisterHealthRoutes(e, srv.health)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/genres", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), apperror.ErrVersionRequired.Code) {
+		t.Errorf("missing header when required = %d %s, want 400 %s", rec.Code, rec.Body.String(), apperror.ErrVersionRequired.Code)
+	}
+	// ロードバランサーや監視はヘッダーを送らない
+	for _, path := range []string{"/health", "/livez", "/readyz", "/version"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s without header when required = %d, want %d", path, rec.Code, http.StatusOK)
+		}
+	}
+}
+
+func TestReadyzUnavailable(t *testing.T) {
+	get := func(srv *testServer) (int, handler.ReadinessResponse) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		srv.echo.ServeHTTP(rec, newRequest(t, routeTest{method: http.MethodGet, path: "/readyz"}))
+		var body handler.ReadinessResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode response: %v\nbody: %s", err, rec.Body.String())
+		}
+		return rec.Code, body
+	}
+
+	// 依存先の1つが失敗すると503になり、他の確認結果も返す
+	srv := newTestServer(t)
+	srv.health.Checks = append(srv.health.Checks, handler.ReadinessCheck{
+		Name:  "geocoder",
+		Check: func(ctx context.Context) error { return errors.New("GOOGLE_MAPS_API_KEY is not set") },
+	})
+	code, body := get(srv)
+	if code != http.StatusServiceUnavailable || body.Status != "unavailable" {
+		t.Errorf("readyz with failing check = %d %+v, want 503 unavailable", code, body)
+	}
+	// エラー内容（接続先やファイルのパスなど）は返さない
+	if body.Checks["database"] != "ok" || body.Checks["geocoder"] != "unavailable" {
+		t.Errorf("checks = %v, want database ok and geocoder unavailable without error details", body.Checks)
+	}
+
+	// 停止処理中は依存先に関係なく503になるが、死活監視は成功する
+	srv = newTestServer(t)
+	srv.health.MarkShuttingDown()
+	if code, body := get(srv); code != http.StatusServiceUnavailable || body.Checks["server"] != "shutting down" {
+		t.Errorf("readyz while shutting down = %d %+v, want 503", code, body)
+	}
+	rec := httptest.NewRecorder()
+	srv.echo.ServeHTTP(rec, newRequest(t, routeTest{method: http.MethodGet, path: "/livez"}))
+	if rec.Code != http.StatusOK {
+		t.Errorf("livez while shutting down = %d, want 200", rec.Code)
+	}
+}
+
+// リクエスト数がルートのパターンごとに記録され、業務上の件数が増えることを確認する
+func TestMetricsRecordsRequests(t *testing.T) {
+	srv := newTestServer(t)
+	before := testutil.ToFloat64(metrics.CheckinsCreated)
+
+	rec := httptest.NewRecorder()
+	srv.echo.ServeHTTP(rec, newRequest(t, routeTest{
+		method: http.MethodPost,
+		path:   "/api/checkins",
+		token:  registeredToken,
+		body:   `{"seichi_id":5}`,
+	}))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d\nbody: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	srv.echo.ServeHTTP(httptest.NewRecorder(), newRequest(t, routeTest{method: http.MethodGet, path: "/api/users/4", token: registeredToken}))
+
+	if got := testutil.ToFloat64(metrics.CheckinsCreated); got != before+1 {
+		t.Errorf("checkins_created_total = %v, want %v", got, before+1)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.echo.ServeHTTP(rec, newRequest(t, routeTest{method: http.MethodGet, path: "/metrics"}))
+	for _, want := range []string{
+		`seicheese_http_requests_total{method="POST",route="/api/checkins",status="200"}`,
+		// IDを含むパスではなく登録時のパスで集計する
+		`route="/api/users/:id"`,
+		"seicheese_checkins_created_total",
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("metrics do not contain %s", want)
+		}
+	}
+}
+
+// 上限を超えると429とRetry-Afterを返し、利用者ごとに別のバケットで数える
+func TestRateLimit(t *testing.T) {
+	srv := newTestServer(t)
+	srv.limiter.Routes["POST /api/contents/register"] = ratelimit.Limit{Requests: 1, Per: time.Minute}
+	srv.limiter.Routes["POST /auth/validate"] = ratelimit.Limit{Requests: 2, Per: time.Minute}
+
+	register := func(token string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.echo.ServeHTTP(rec, newRequest(t, routeTest{
+			method: http.MethodPost,
+			path:   "/api/contents/register",
+			token:  token,
+			body:   `{"content_name":"天気の子","genre_id":1}`,
+		}))
+		return rec
+	}
+
+	if rec := register(registeredToken); rec.Code != http.StatusCreated {
+		t.Fatalf("first request = %d, want %d\nbody: %s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+	rec := register(registeredToken)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get(echo.HeaderRetryAfter) != "60" {
+		t.Errorf("second request = %d Retry-After %q, want 429 and 60", rec.Code, rec.Header().Get(echo.HeaderRetryAfter))
+	}
+	var body struct {
+		Error struct {
+			Code    string
+			Details map[string]int
+		}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error.Code != "TOO_MANY_REQUESTS" || body.Error.Details["retry_after"] != 60 {
+		t.Errorf("body = %s, want TOO_MANY_REQUESTS with retry_after", rec.Body.String())
+	}
+	// 別のユーザーは制限されない
+	if rec := register(unregisteredToken); rec.Code != http.StatusCreated {
+		t.Errorf("other user = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	// 上限を設定していないルートは制限されない
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		srv.echo.ServeHTTP(rec, newRequest(t, routeTest{method: http.MethodGet, path: "/api/contents/search", token: registeredToken}))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unlimited route = %d, want %d", rec.Code, http.StatusOK)
+		}
+	}
+
+	// 認証前のルートはIPアドレスで数える
+	validate := func(remoteAddr, forwardedFor string) int {
+		req := newRequest(t, routeTest{method: http.MethodPost, path: "/auth/validate", body: `{"version":"1.0.0"}`})
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+			req.Header.Set(echo.HeaderXRealIP, forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		srv.echo.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	for i, want := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		if got := validate("192.0.2.1:1234", ""); got != want {
+			t.Errorf("validate #%d from same IP = %d, want %d", i+1, got, want)
+		}
+	}
+	// クライアントが送るX-Forwarded-For・X-Real-IPを変えても別のIPアドレスとして数えない
+	for i := 0; i < 3; i++ {
+		if got := validate("192.0.2.1:1234", fmt.Sprintf("203.0.113.%d", i+1)); got != http.StatusTooManyRequests {
+			t.Errorf("validate with spoofed X-Forwarded-For #%d = %d, want %d", i+1, got, http.StatusTooManyRequests)
+		}
+	}
+	if got := validate("192.0.2.2:1234", ""); got != http.StatusUnauthorized {
+		t.Errorf("validate from other IP = %d, want %d", got, http.StatusUnauthorized)
+	}
+}
+
+func TestUploadAvatarStoresBlob(t *testing.T) {
+	srv := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	srv.echo.ServeHTTP(rec, newRequest(t, routeTest{
+		method: http.MethodPut,
+		path:   "/api/users/me/avatar",
+		token:  registeredToken,
+		avatar: pngData,
+	}))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d\nbody: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	if got := srv.blobs.blobs["avatars/4"]; !bytes.Equal(got, pngData) {
+		t.Errorf("stored avatar = %q, want %q", got, pngData)
+	}
+}
+
+func TestUploadAvatarKeepsStorageQuery(t *testing.T) {
+	srv := newTestServer(t)
+	// Firebase Storageと同じく、クエリを含むダウンロード用URLを返す
+	srv.blobs.url = func(key string) string {
+		return "https://firebasestorage.googleapis.com/v0/b/seicheese.appspot.com/o/" + url.PathEscape(key) + "?alt=media"
+	}
+
+	rec := httptest.NewRecorder()
+	srv.echo.ServeHTTP(rec, newRequest(t, routeTest{
+		method: http.MethodPut,
+		path:   "/api/users/me/avatar",
+		token:  registeredToken,
+		avatar: pngData,
+	}))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d\nbody: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var body struct {
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(body.AvatarURL)
+	if err != nil {
+		t.Fatalf("avatar_url %q: %v", body.AvatarURL, err)
+	}
+	if u.EscapedPath() != "/v0/b/seicheese.appspot.com/o/avatars%2F4" || u.Query().Get("alt") != "media" || u.Query().Get("v") == "" {
+		t.Errorf("avatar_url = %q, want storage path with alt=media and cache-busting v", body.AvatarURL)
+	}
+}
+
+func newRequest(t *testing.T, tt routeTest) *http.Request {
+	t.Helper()
+
+	path := tt.path
+	if path == "" {
+		path = tt.route
+	}
+
+	var body io.Reader
+	contentType := ""
+	switch {
+	case tt.avatar != nil:
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		fw, err := mw.CreateFormFile("avatar", "avatar.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(tt.avatar); err != nil {
+			t.Fatal(err)
+		}
+		if err := mw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		body = &buf
+		contentType = mw.FormDataContentType()
+	case tt.body != "":
+		body = strings.NewReader(tt.body)
+		contentType = echo.MIMEApplicationJSON
+	}
+
+	req := httptest.NewRequest(tt.method, path, body)
+	if contentType != "" {
+		req.Header.Set(echo.HeaderContentType, contentType)
+	}
+	if tt.token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+tt.token)
+	}
+	if tt.lang != "" {
+		req.Header.Set("Accept-Language", tt.lang)
+	}
+	return req
+}
+
+var (
+	timestampPattern  = regexp.MustCompile(`"\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})"`)
+	cacheBustPattern  = regexp.MustCompile(`\?v=\d+`)
+	timestampRedacted = `"<timestamp>"`
+)
+
+// normalizeJSON はキー順を揃えて整形し、実行ごとに変わる値を置き換える
+func normalizeJSON(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("response is not JSON: %v\nbody: %s", err, data)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		t.Fatal(err)
+	}
+
+	out := timestampPattern.ReplaceAll(buf.Bytes(), []byte(timestampRedacted))
+	return cacheBustPattern.ReplaceAll(out, []byte("?v=<unix>"))
+}
+
+func assertGolden(t *testing.T, name string, body []byte) {
+	t.Helper()
+
+	got := normalizeJSON(t, body)
+	path := filepath.Join("testdata", name+".golden.json")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("response does not match %s (run with -update if the change is intended)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
@@ -1,17 +1,20 @@
 package router
 
 import (
+	"seicheese/internal/auth"
 	"seicheese/internal/handler"
 	"seicheese/internal/middleware"
+	"seicheese/internal/ratelimit"
+	"seicheese/internal/repository"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterSeichiRoutes(e *echo.Echo, seichiHandler *handler.SeichiHandler, authClient *auth.Client) {
+func RegisterSeichiRoutes(e *echo.Echo, seichiHandler *handler.SeichiHandler, verifier auth.TokenVerifier, authConfig *auth.Config, users repository.UserRepository, limiter *ratelimit.Limiter) {
 	seichiGroup := e.Group("/api/seichi")
-	seichiGroup.Use(middleware.FirebaseAuthMiddleware(authClient))
+	seichiGroup.Use(middleware.FirebaseAuthMiddleware(verifier, authConfig))
+	seichiGroup.Use(middleware.RateLimitMiddleware(limiter))
 
-	seichiGroup.POST("/register", seichiHandler.RegisterSeichi)
+	seichiGroup.POST("/register", seichiHandler.RegisterSeichi, middleware.LoadUserMiddleware(users))
 	seichiGroup.GET("/list", seichiHandler.GetSeichies)
 }
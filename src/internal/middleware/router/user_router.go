@@ -1,22 +1,42 @@
 package router
 
 import (
+	"seicheese/internal/auth"
 	"seicheese/internal/handler"
 	"seicheese/internal/middleware"
+	"seicheese/internal/ratelimit"
+	"seicheese/internal/repository"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterUserRoutes(e *echo.Echo, userHandler *handler.UserHandler, authClient *auth.Client) {
+func RegisterUserRoutes(e *echo.Echo, userHandler *handler.UserHandler, verifier auth.TokenVerifier, authConfig *auth.Config, users repository.UserRepository, limiter *ratelimit.Limiter) {
 	// ユーザー関連のルーティンググループ
 	userGroup := e.Group("/api/users")
 
 	// すべてのエンドポイントで認証が必要
-	userGroup.Use(middleware.FirebaseAuthMiddleware(authClient))
+	userGroup.Use(middleware.FirebaseAuthMiddleware(verifier, authConfig))
+	userGroup.Use(middleware.RateLimitMiddleware(limiter))
+
+	// 登録済みユーザーのみ利用できるエンドポイント用
+	loadUser := middleware.LoadUserMiddleware(users)
 
 	// ユーザー情報の取得
-	userGroup.GET("/me", userHandler.GetUser)
+	userGroup.GET("/me", userHandler.GetUser, loadUser)
+
+	// プロフィールの更新
+	userGroup.PATCH("/me", userHandler.UpdateProfile, loadUser)
+
+	// アカウントの削除とデータのエクスポート
+	userGroup.DELETE("/me", userHandler.DeleteAccount, loadUser)
+	userGroup.GET("/me/export", userHandler.ExportData, loadUser)
+
+	// アバター画像のアップロード・削除
+	userGroup.PUT("/me/avatar", userHandler.UploadAvatar, loadUser)
+	userGroup.DELETE("/me/avatar", userHandler.DeleteAvatar, loadUser)
+
+	// 公開プロフィールの取得
+	userGroup.GET("/:id", userHandler.GetPublicProfile)
 
 	// ユーザー登録
 	userGroup.POST("", userHandler.RegisterUser)
@@ -0,0 +1,66 @@
+// Seicheese-Backend/src/internal/middleware/user.go
+
+package middleware
+
+import (
+	"errors"
+	"fmt"
+
+	"seicheese/internal/apperror"
+	"seicheese/internal/auth"
+	"seicheese/internal/repository"
+	"seicheese/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// echo.Contextに保存する値のキー
+const (
+	ContextKeyPrincipal = "principal"
+	ContextKeyUser      = "user"
+)
+
+// PrincipalFromContext はFirebaseAuthMiddlewareで保存した利用者を取得
+func PrincipalFromContext(c echo.Context) (*auth.Principal, bool) {
+	principal, ok := c.Get(ContextKeyPrincipal).(*auth.Principal)
+	return principal, ok && principal != nil
+}
+
+// UIDFromContext はFirebaseAuthMiddlewareで保存した利用者のFirebase UIDを取得
+func UIDFromContext(c echo.Context) (string, bool) {
+	principal, ok := PrincipalFromContext(c)
+	if !ok {
+		return "", false
+	}
+	return principal.UID, principal.UID != ""
+}
+
+// UserFromContext はLoadUserMiddlewareで保存したユーザーを取得
+func UserFromContext(c echo.Context) (*models.User, bool) {
+	user, ok := c.Get(ContextKeyUser).(*models.User)
+	return user, ok && user != nil
+}
+
+// LoadUserMiddleware はFirebase UIDに対応するユーザーを1度だけ取得してコンテキストに保存する
+// FirebaseAuthMiddlewareの後に適用すること
+func LoadUserMiddleware(users repository.UserRepository) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			uid, ok := UIDFromContext(c)
+			if !ok {
+				return apperror.ErrTokenRequired
+			}
+
+			user, err := users.FindByFirebaseID(c.Request().Context(), uid)
+			if errors.Is(err, repository.ErrNotFound) {
+				return apperror.ErrUserNotRegistered
+			}
+			if err != nil {
+				return apperror.ErrInternal.Wrap(fmt.Errorf("fetch user: %w", err))
+			}
+
+			c.Set(ContextKeyUser, user)
+			return next(c)
+		}
+	}
+}
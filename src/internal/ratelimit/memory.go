@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 満杯になったバケットを削除する間隔
+const sweepInterval = time.Minute
+
+var _ Store = (*MemoryStore)(nil)
+
+// MemoryStore はプロセス内にバケットを保持するStore（複数台の間では共有されない）
+// 満杯まで補充されたバケットは初期状態と同じため、定期的に削除してメモリを解放する
+type MemoryStore struct {
+	// 現在時刻（nilの場合はtime.Now、テスト用）
+	Now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens   float64
+	updated  time.Time
+	capacity float64
+	interval time.Duration
+}
+
+// NewMemoryStore は空のMemoryStoreを作成
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	now := time.Now()
+	if s.Now != nil {
+		now = s.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+		s.lastSweep = now
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Capacity()), updated: now}
+		s.buckets[key] = b
+	}
+	// 設定が変わった場合も新しい上限で補充する
+	b.capacity = float64(limit.Capacity())
+	b.interval = limit.Interval()
+	b.refill(now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+	return false, time.Duration((1 - b.tokens) * float64(b.interval)), nil
+}
+
+// refill は前回からの経過時間に応じてトークンを補充する
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = min(b.capacity, b.tokens+float64(elapsed)/float64(b.interval))
+	}
+	b.updated = now
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= b.capacity {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// Len は保持しているバケットの数
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
@@ -0,0 +1,117 @@
+// Package ratelimit はトークンバケットによるリクエスト数の制限を提供する
+//
+// バケットはルート（"POST /api/seichi/register" のようなメソッドと登録時のパス）と
+// 利用者（Firebase UID、未認証の場合はIPアドレス）の組ごとに持つ
+// バケットの状態はStoreに保存する（1台ならMemoryStore、複数台で共有する場合はRedisなどで実装する）
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Limit はルートごとの上限
+// Perあたり平均Requests回まで受け付け、短時間にはBurst回までまとめて受け付ける
+type Limit struct {
+	// 期間あたりのリクエスト数（0の場合は制限しない）
+	Requests int `yaml:"requests"`
+	// 期間（例: 1m）
+	Per time.Duration `yaml:"per"`
+	// まとめて受け付ける回数（0の場合はRequestsと同じ）
+	Burst int `yaml:"burst"`
+}
+
+// Unlimited は制限しないかどうか
+func (l Limit) Unlimited() bool {
+	return l.Requests == 0
+}
+
+// Interval はトークンが1つ補充されるまでの時間
+func (l Limit) Interval() time.Duration {
+	return l.Per / time.Duration(l.Requests)
+}
+
+// Capacity はバケットに貯められるトークンの数
+func (l Limit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+func (l Limit) validate() error {
+	if l.Requests < 0 || l.Burst < 0 {
+		return errors.New("requests and burst must not be negative")
+	}
+	if !l.Unlimited() && l.Per <= 0 {
+		return errors.New("per must be positive")
+	}
+	return nil
+}
+
+// トークンバケットの設定構造体
+type Config struct {
+	// 制限を有効にするか
+	Enabled bool `yaml:"enabled"`
+	// ルート（"METHOD /path"）ごとの上限（設定のないルートは制限しない）
+	Routes map[string]Limit `yaml:"routes"`
+}
+
+// Validate はルートの形式と上限を検証する
+func (c *Config) Validate() error {
+	var errs []error
+	for route, limit := range c.Routes {
+		if method, path, ok := strings.Cut(route, " "); !ok || method == "" || !strings.HasPrefix(path, "/") {
+			errs = append(errs, fmt.Errorf("rate_limit route must be \"METHOD /path\": %q", route))
+			continue
+		}
+		if err := limit.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("rate_limit route %q: %w", route, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Store はバケットの状態を保持する
+type Store interface {
+	// Take はkeyのバケットからトークンを1つ取り出す
+	// 取り出せない場合はfalseと、次のトークンが補充されるまでの時間を返す
+	Take(ctx context.Context, key string, limit Limit) (ok bool, retryAfter time.Duration, err error)
+}
+
+// Limiter はルートごとの上限でStoreのバケットを消費する
+type Limiter struct {
+	Store Store
+	// ルート（"METHOD /path"）ごとの上限
+	Routes map[string]Limit
+}
+
+// NewLimiter は設定からLimiterを作成する（無効の場合はnilを返す）
+func NewLimiter(config *Config, store Store) *Limiter {
+	if !config.Enabled {
+		return nil
+	}
+	return &Limiter{Store: store, Routes: config.Routes}
+}
+
+// Allow はrouteへのsubject（UIDまたはIPアドレス）のリクエストを受け付けるか判定する
+// 受け付けない場合は再試行までの時間を返す
+func (l *Limiter) Allow(ctx context.Context, route, subject string) (bool, time.Duration, error) {
+	if l == nil {
+		return true, 0, nil
+	}
+	limit, ok := l.Routes[route]
+	if !ok || limit.Unlimited() {
+		return true, 0, nil
+	}
+	return l.Store.Take(ctx, route+"|"+subject, limit)
+}
+
+// RetryAfterSeconds はRetry-Afterヘッダーに設定する秒数（切り上げ、最低1秒）
+func RetryAfterSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,170 @@
+package ratelimit_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"seicheese/internal/ratelimit"
+)
+
+// fakeClock はテストで進める時計
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newStore() (*ratelimit.MemoryStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 11, 1, 9, 0, 0, 0, time.UTC)}
+	store := ratelimit.NewMemoryStore()
+	store.Now = clock.Now
+	return store, clock
+}
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newStore()
+	// 1分あたり6回（10秒ごとに1回補充）、まとめて3回まで
+	limit := ratelimit.Limit{Requests: 6, Per: time.Minute, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		if ok, _, err := store.Take(ctx, "a", limit); !ok || err != nil {
+			t.Fatalf("request %d within burst = %v, %v; want allowed", i+1, ok, err)
+		}
+	}
+	ok, retryAfter, err := store.Take(ctx, "a", limit)
+	if ok || err != nil || retryAfter != 10*time.Second {
+		t.Errorf("request over burst = %v, %v, %v; want rejected with 10s", ok, retryAfter, err)
+	}
+
+	// 他のキーは別のバケット
+	if ok, _, _ := store.Take(ctx, "b", limit); !ok {
+		t.Error("other key was rejected")
+	}
+
+	// 4秒後は残り6秒
+	clock.Advance(4 * time.Second)
+	if ok, retryAfter, _ := store.Take(ctx, "a", limit); ok || retryAfter != 6*time.Second {
+		t.Errorf("after 4s = %v, %v; want rejected with 6s", ok, retryAfter)
+	}
+	// 補充されたら1回だけ受け付ける
+	clock.Advance(6 * time.Second)
+	if ok, _, _ := store.Take(ctx, "a", limit); !ok {
+		t.Error("after refill = rejected, want allowed")
+	}
+	if ok, _, _ := store.Take(ctx, "a", limit); ok {
+		t.Error("second request after refill = allowed, want rejected")
+	}
+
+	// 長時間経過してもBurstを超えて貯まらない
+	clock.Advance(time.Hour)
+	for i := 0; i < 3; i++ {
+		store.Take(ctx, "a", limit)
+	}
+	if ok, _, _ := store.Take(ctx, "a", limit); ok {
+		t.Error("tokens exceeded burst after idle period")
+	}
+}
+
+func TestMemoryStoreBurstDefaultsToRequests(t *testing.T) {
+	store, _ := newStore()
+	limit := ratelimit.Limit{Requests: 2, Per: time.Second}
+	for i, want := range []bool{true, true, false} {
+		if ok, _, _ := store.Take(context.Background(), "a", limit); ok != want {
+			t.Errorf("request %d = %v, want %v", i+1, ok, want)
+		}
+	}
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newStore()
+	limit := ratelimit.Limit{Requests: 1, Per: time.Minute}
+
+	store.Take(ctx, "idle", limit)
+	clock.Advance(30 * time.Second)
+	store.Take(ctx, "active", limit)
+	if store.Len() != 2 {
+		t.Fatalf("buckets = %d, want 2", store.Len())
+	}
+
+	// 1分後の整理で満杯になった"idle"だけが削除される
+	clock.Advance(40 * time.Second)
+	store.Take(ctx, "new", limit)
+	if store.Len() != 2 {
+		t.Errorf("buckets after sweep = %d, want 2 (active and new)", store.Len())
+	}
+}
+
+func TestLimiterAllow(t *testing.T) {
+	ctx := context.Background()
+
+	// nilのLimiterは制限しない
+	var disabled *ratelimit.Limiter
+	if ok, _, err := disabled.Allow(ctx, "POST /api/checkins", "uid:a"); !ok || err != nil {
+		t.Errorf("nil limiter = %v, %v; want allowed", ok, err)
+	}
+	if limiter := ratelimit.NewLimiter(&ratelimit.Config{Enabled: false}, ratelimit.NewMemoryStore()); limiter != nil {
+		t.Error("NewLimiter with Enabled=false should return nil")
+	}
+
+	store, _ := newStore()
+	limiter := ratelimit.NewLimiter(&ratelimit.Config{
+		Enabled: true,
+		Routes: map[string]ratelimit.Limit{
+			"POST /api/checkins":     {Requests: 1, Per: time.Minute},
+			"POST /api/seichi/other": {Requests: 0},
+		},
+	}, store)
+
+	if ok, _, _ := limiter.Allow(ctx, "POST /api/checkins", "uid:a"); !ok {
+		t.Error("first request rejected")
+	}
+	if ok, retryAfter, _ := limiter.Allow(ctx, "POST /api/checkins", "uid:a"); ok || retryAfter != time.Minute {
+		t.Errorf("second request = %v, %v; want rejected with 1m", ok, retryAfter)
+	}
+	if ok, _, _ := limiter.Allow(ctx, "POST /api/checkins", "uid:b"); !ok {
+		t.Error("other subject rejected")
+	}
+	for i := 0; i < 3; i++ {
+		if ok, _, _ := limiter.Allow(ctx, "POST /api/seichi/other", "uid:a"); !ok {
+			t.Error("route with requests=0 was limited")
+		}
+		if ok, _, _ := limiter.Allow(ctx, "GET /api/genres", "uid:a"); !ok {
+			t.Error("route without limit was limited")
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		route   string
+		limit   ratelimit.Limit
+		wantErr string
+	}{
+		{"POST /api/checkins", ratelimit.Limit{Requests: 10, Per: time.Minute}, ""},
+		{"POST /api/checkins", ratelimit.Limit{}, ""},
+		{"/api/checkins", ratelimit.Limit{Requests: 10, Per: time.Minute}, "METHOD /path"},
+		{"POST api/checkins", ratelimit.Limit{Requests: 10, Per: time.Minute}, "METHOD /path"},
+		{"POST /api/checkins", ratelimit.Limit{Requests: 10}, "per must be positive"},
+		{"POST /api/checkins", ratelimit.Limit{Requests: -1, Per: time.Minute}, "must not be negative"},
+	}
+	for _, tt := range tests {
+		config := ratelimit.Config{Enabled: true, Routes: map[string]ratelimit.Limit{tt.route: tt.limit}}
+		err := config.Validate()
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("Validate(%q, %+v) = %v, want %q", tt.route, tt.limit, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	for d, want := range map[time.Duration]int{0: 1, 300 * time.Millisecond: 1, 10 * time.Second: 10, 10*time.Second + time.Millisecond: 11} {
+		if got := ratelimit.RetryAfterSeconds(d); got != want {
+			t.Errorf("RetryAfterSeconds(%v) = %d, want %d", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"seicheese/internal/infrastructure/database"
+	"seicheese/models"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+type checkinRepository struct {
+	db database.DB
+}
+
+// NewCheckinRepository はsqlboilerを使うCheckinRepositoryを作成
+func NewCheckinRepository(db database.DB) CheckinRepository {
+	return &checkinRepository{db: db}
+}
+
+func (r *checkinRepository) ListByUser(ctx context.Context, userID uint) (models.CheckinLogSlice, error) {
+	return models.CheckinLogs(
+		models.CheckinLogWhere.UserID.EQ(userID),
+		qm.OrderBy(models.CheckinLogColumns.CreatedAt+" DESC"),
+	).All(ctx, r.db)
+}
+
+func (r *checkinRepository) Create(ctx context.Context, checkin *models.CheckinLog) error {
+	return checkin.Insert(ctx, r.db, boil.Infer())
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"seicheese/internal/infrastructure/database"
+	"seicheese/models"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+type contentRepository struct {
+	db database.DB
+}
+
+// NewContentRepository はsqlboilerを使うContentRepositoryを作成
+func NewContentRepository(db database.DB) ContentRepository {
+	return &contentRepository{db: db}
+}
+
+func (r *contentRepository) Create(ctx context.Context, content *models.Content) error {
+	return content.Insert(ctx, r.db, boil.Infer())
+}
+
+func (r *contentRepository) Search(ctx context.Context, query string) (models.ContentSlice, error) {
+	return models.Contents(
+		qm.Where("content_name LIKE ?", "%"+query+"%"),
+		qm.Or("content_name_en LIKE ?", "%"+query+"%"),
+	).All(ctx, r.db)
+}
+
+func (r *contentRepository) Exists(ctx context.Context, contentID int) (bool, error) {
+	return models.ContentExists(ctx, r.db, contentID)
+}
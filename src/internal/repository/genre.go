@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"seicheese/internal/infrastructure/database"
+	"seicheese/models"
+)
+
+type genreRepository struct {
+	db database.DB
+}
+
+// NewGenreRepository はsqlboilerを使うGenreRepositoryを作成
+func NewGenreRepository(db database.DB) GenreRepository {
+	return &genreRepository{db: db}
+}
+
+func (r *genreRepository) List(ctx context.Context) (models.GenreSlice, error) {
+	return models.Genres().All(ctx, r.db)
+}
+
+func (r *genreRepository) CountByIDs(ctx context.Context, genreIDs []int) (int64, error) {
+	if len(genreIDs) == 0 {
+		return 0, nil
+	}
+	return models.Genres(models.GenreWhere.GenreID.IN(genreIDs)).Count(ctx, r.db)
+}
+
+func (r *genreRepository) Exists(ctx context.Context, genreID int) (bool, error) {
+	return models.GenreExists(ctx, r.db, genreID)
+}
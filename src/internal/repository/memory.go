@@ -0,0 +1,563 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"seicheese/models"
+
+	"github.com/volatiletech/null/v8"
+)
+
+// MemoryStore はメモリ上のリポジトリが共有するデータ（テスト用）
+// 保存・取得のたびに値をコピーするため、呼び出し側で変更しても保存済みのデータには影響しない
+type MemoryStore struct {
+	mu sync.Mutex
+
+	genres    []*models.Genre
+	contents  []*models.Content
+	places    []*models.Place
+	users     []*models.User
+	seichies  []*models.Seichy
+	checkins  []*models.CheckinLog
+	points    map[uint]*models.Point
+	pointLogs []*models.PointLog
+
+	lastID int
+}
+
+// NewMemoryStore は空のMemoryStoreを作成
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{points: map[uint]*models.Point{}}
+}
+
+// nextID は採番したIDを返す（全テーブルで共通の連番）
+func (s *MemoryStore) nextID() int {
+	s.lastID++
+	return s.lastID
+}
+
+// AddGenre はジャンルを追加する（GenreIDが0の場合は採番する）
+func (s *MemoryStore) AddGenre(genre *models.Genre) *models.Genre {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if genre.GenreID == 0 {
+		genre.GenreID = s.nextID()
+	}
+	stored := *genre
+	s.genres = append(s.genres, &stored)
+	return genre
+}
+
+// AddContent は作品を追加する（ContentIDが0の場合は採番する）
+func (s *MemoryStore) AddContent(content *models.Content) *models.Content {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.insertContent(content)
+	return content
+}
+
+// AddPlace は場所を追加する（PlaceIDが0の場合は採番する）
+func (s *MemoryStore) AddPlace(place *models.Place) *models.Place {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.insertPlace(place)
+	return place
+}
+
+// AddUser はユーザーを追加する（UserIDが0の場合は採番する）
+func (s *MemoryStore) AddUser(user *models.User) *models.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.insertUser(user)
+	return user
+}
+
+// AddSeichi は聖地を追加する（SeichiIDが0の場合は採番する）
+func (s *MemoryStore) AddSeichi(seichi *models.Seichy) *models.Seichy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.insertSeichi(seichi)
+	return seichi
+}
+
+// AddCheckin はチェックイン履歴を追加する
+func (s *MemoryStore) AddCheckin(checkin *models.CheckinLog) *models.CheckinLog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.insertCheckin(checkin)
+	return checkin
+}
+
+// SetPoint はユーザーのポイントを設定する
+func (s *MemoryStore) SetPoint(point *models.Point) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *point
+	s.points[point.UserID] = &stored
+}
+
+// AddPointLog はポイント履歴を追加する
+func (s *MemoryStore) AddPointLog(log *models.PointLog) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *log
+	s.pointLogs = append(s.pointLogs, &stored)
+}
+
+// Users は保存されているユーザーのコピーを返す
+func (s *MemoryStore) Users() []*models.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	users := make([]*models.User, len(s.users))
+	for i, user := range s.users {
+		stored := *user
+		users[i] = &stored
+	}
+	return users
+}
+
+func now() null.Time {
+	return null.TimeFrom(time.Now())
+}
+
+func (s *MemoryStore) insertContent(content *models.Content) {
+	if content.ContentID == 0 {
+		content.ContentID = s.nextID()
+	}
+	if !content.CreatedAt.Valid {
+		content.CreatedAt, content.UpdatedAt = now(), now()
+	}
+	stored := *content
+	stored.R = nil
+	s.contents = append(s.contents, &stored)
+}
+
+func (s *MemoryStore) insertPlace(place *models.Place) {
+	if place.PlaceID == 0 {
+		place.PlaceID = s.nextID()
+	}
+	if !place.CreatedAt.Valid {
+		place.CreatedAt, place.UpdatedAt = now(), now()
+	}
+	stored := *place
+	stored.R = nil
+	s.places = append(s.places, &stored)
+}
+
+func (s *MemoryStore) insertUser(user *models.User) {
+	if user.UserID == 0 {
+		user.UserID = uint(s.nextID())
+	}
+	if !user.CreatedAt.Valid {
+		user.CreatedAt, user.UpdatedAt = now(), now()
+	}
+	stored := *user
+	stored.R = nil
+	s.users = append(s.users, &stored)
+}
+
+func (s *MemoryStore) insertSeichi(seichi *models.Seichy) {
+	if seichi.SeichiID == 0 {
+		seichi.SeichiID = s.nextID()
+	}
+	if !seichi.CreatedAt.Valid {
+		seichi.CreatedAt, seichi.UpdatedAt = now(), now()
+	}
+	stored := *seichi
+	stored.R = nil
+	s.seichies = append(s.seichies, &stored)
+}
+
+func (s *MemoryStore) insertCheckin(checkin *models.CheckinLog) {
+	if checkin.CreatedAt.IsZero() {
+		checkin.CreatedAt = time.Now()
+	}
+	stored := *checkin
+	stored.R = nil
+	s.checkins = append(s.checkins, &stored)
+}
+
+func (s *MemoryStore) findContent(contentID int) *models.Content {
+	for _, content := range s.contents {
+		if content.ContentID == contentID {
+			return content
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) findPlace(placeID int) *models.Place {
+	for _, place := range s.places {
+		if place.PlaceID == placeID {
+			return place
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) findOrCreatePlace(address, zipCode string) *models.Place {
+	for _, place := range s.places {
+		if place.Address == address {
+			copied := *place
+			return &copied
+		}
+	}
+	place := &models.Place{Address: address, ZipCode: zipCode}
+	s.insertPlace(place)
+	return place
+}
+
+type memorySeichiRepository struct {
+	store *MemoryStore
+}
+
+func (r *memorySeichiRepository) List(ctx context.Context, limit, offset int) (models.SeichySlice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	seichies := models.SeichySlice{}
+	for i, seichi := range r.store.seichies {
+		if i < offset {
+			continue
+		}
+		if len(seichies) >= limit {
+			break
+		}
+		copied := *seichi
+		copied.R = copied.R.NewStruct()
+		if content := r.store.findContent(seichi.ContentID); content != nil {
+			contentCopy := *content
+			copied.R.Content = &contentCopy
+		}
+		if place := r.store.findPlace(seichi.PlaceID); place != nil {
+			placeCopy := *place
+			copied.R.Place = &placeCopy
+		}
+		seichies = append(seichies, &copied)
+	}
+	return seichies, nil
+}
+
+func (r *memorySeichiRepository) ListByUser(ctx context.Context, userID uint) (models.SeichySlice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	seichies := models.SeichySlice{}
+	for _, seichi := range r.store.seichies {
+		if seichi.UserID.Valid && seichi.UserID.Uint == userID {
+			copied := *seichi
+			seichies = append(seichies, &copied)
+		}
+	}
+	return seichies, nil
+}
+
+func (r *memorySeichiRepository) Create(ctx context.Context, seichi *models.Seichy, address, zipCode string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	seichi.PlaceID = r.store.findOrCreatePlace(address, zipCode).PlaceID
+	r.store.insertSeichi(seichi)
+	return nil
+}
+
+func (r *memorySeichiRepository) Exists(ctx context.Context, seichiID int) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, seichi := range r.store.seichies {
+		if seichi.SeichiID == seichiID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memorySeichiRepository) Clusters(ctx context.Context) ([]SeichiCluster, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// SQLと同じく緯度経度を0.01度単位のグリッドでまとめる
+	type cell struct{ lat, lng float64 }
+	type sum struct {
+		lat, lng float64
+		count    int
+	}
+	var order []cell
+	sums := map[cell]*sum{}
+	for _, seichi := range r.store.seichies {
+		lat, _ := seichi.Latitude.Float64()
+		lng, _ := seichi.Longitude.Float64()
+		key := cell{math.Floor(lat * 100), math.Floor(lng * 100)}
+		if sums[key] == nil {
+			sums[key] = &sum{}
+			order = append(order, key)
+		}
+		sums[key].lat += lat
+		sums[key].lng += lng
+		sums[key].count++
+	}
+
+	var clusters []SeichiCluster
+	for _, key := range order {
+		s := sums[key]
+		if s.count <= 1 {
+			continue
+		}
+		clusters = append(clusters, SeichiCluster{
+			Latitude:  math.Round(s.lat/float64(s.count)*10000) / 10000,
+			Longitude: math.Round(s.lng/float64(s.count)*10000) / 10000,
+			Count:     s.count,
+		})
+	}
+	return clusters, nil
+}
+
+type memoryContentRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryContentRepository) Create(ctx context.Context, content *models.Content) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.insertContent(content)
+	return nil
+}
+
+func (r *memoryContentRepository) Search(ctx context.Context, query string) (models.ContentSlice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// MySQLのLIKEと同じく大文字小文字を区別しない
+	query = strings.ToLower(query)
+	contents := models.ContentSlice{}
+	for _, content := range r.store.contents {
+		if strings.Contains(strings.ToLower(content.ContentName), query) ||
+			(content.ContentNameEn.Valid && strings.Contains(strings.ToLower(content.ContentNameEn.String), query)) {
+			copied := *content
+			contents = append(contents, &copied)
+		}
+	}
+	return contents, nil
+}
+
+func (r *memoryContentRepository) Exists(ctx context.Context, contentID int) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.findContent(contentID) != nil, nil
+}
+
+type memoryGenreRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryGenreRepository) List(ctx context.Context) (models.GenreSlice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	genres := models.GenreSlice{}
+	for _, genre := range r.store.genres {
+		copied := *genre
+		genres = append(genres, &copied)
+	}
+	return genres, nil
+}
+
+func (r *memoryGenreRepository) CountByIDs(ctx context.Context, genreIDs []int) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	ids := make(map[int]bool, len(genreIDs))
+	for _, id := range genreIDs {
+		ids[id] = true
+	}
+	var count int64
+	for _, genre := range r.store.genres {
+		if ids[genre.GenreID] {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryGenreRepository) Exists(ctx context.Context, genreID int) (bool, error) {
+	count, err := r.CountByIDs(ctx, []int{genreID})
+	return count > 0, err
+}
+
+type memoryUserRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryUserRepository) find(match func(*models.User) bool) (*models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, user := range r.store.users {
+		if match(user) {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryUserRepository) FindByID(ctx context.Context, userID uint) (*models.User, error) {
+	return r.find(func(u *models.User) bool { return u.UserID == userID })
+}
+
+func (r *memoryUserRepository) FindByFirebaseID(ctx context.Context, firebaseID string) (*models.User, error) {
+	return r.find(func(u *models.User) bool { return u.FirebaseID == firebaseID })
+}
+
+func (r *memoryUserRepository) InsertIfNotExists(ctx context.Context, user *models.User) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.users {
+		if existing.FirebaseID == user.FirebaseID {
+			*user = *existing
+			return false, nil
+		}
+	}
+	r.store.insertUser(user)
+	return true, nil
+}
+
+func (r *memoryUserRepository) Update(ctx context.Context, user *models.User, columns ...string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, existing := range r.store.users {
+		if existing.UserID == user.UserID {
+			user.UpdatedAt = now()
+			stored := *user
+			stored.R = nil
+			r.store.users[i] = &stored
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryUserRepository) DeleteAccount(ctx context.Context, user *models.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	users := r.store.users[:0]
+	for _, existing := range r.store.users {
+		if existing.UserID != user.UserID {
+			users = append(users, existing)
+		}
+	}
+	r.store.users = users
+
+	for _, seichi := range r.store.seichies {
+		if seichi.UserID.Valid && seichi.UserID.Uint == user.UserID {
+			seichi.UserID = null.Uint{}
+		}
+	}
+
+	checkins := r.store.checkins[:0]
+	for _, checkin := range r.store.checkins {
+		if checkin.UserID != user.UserID {
+			checkins = append(checkins, checkin)
+		}
+	}
+	r.store.checkins = checkins
+
+	pointLogs := r.store.pointLogs[:0]
+	for _, log := range r.store.pointLogs {
+		if log.UserID != user.UserID {
+			pointLogs = append(pointLogs, log)
+		}
+	}
+	r.store.pointLogs = pointLogs
+	delete(r.store.points, user.UserID)
+	return nil
+}
+
+func (r *memoryUserRepository) Points(ctx context.Context, userID uint) (*models.Point, models.PointLogSlice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var point *models.Point
+	if stored, ok := r.store.points[userID]; ok {
+		copied := *stored
+		point = &copied
+	}
+
+	logs := models.PointLogSlice{}
+	for _, log := range r.store.pointLogs {
+		if log.UserID == userID {
+			copied := *log
+			logs = append(logs, &copied)
+		}
+	}
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].CreatedAt.Before(logs[j].CreatedAt) })
+	return point, logs, nil
+}
+
+type memoryCheckinRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryCheckinRepository) ListByUser(ctx context.Context, userID uint) (models.CheckinLogSlice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	checkins := models.CheckinLogSlice{}
+	for _, checkin := range r.store.checkins {
+		if checkin.UserID == userID {
+			copied := *checkin
+			checkins = append(checkins, &copied)
+		}
+	}
+	sort.SliceStable(checkins, func(i, j int) bool { return checkins[i].CreatedAt.After(checkins[j].CreatedAt) })
+	return checkins, nil
+}
+
+func (r *memoryCheckinRepository) Create(ctx context.Context, checkin *models.CheckinLog) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.insertCheckin(checkin)
+	return nil
+}
+
+type memoryPlaceRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryPlaceRepository) List(ctx context.Context) (models.PlaceSlice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	places := models.PlaceSlice{}
+	for _, place := range r.store.places {
+		copied := *place
+		places = append(places, &copied)
+	}
+	return places, nil
+}
+
+func (r *memoryPlaceRepository) FindOrCreate(ctx context.Context, address, zipCode string) (*models.Place, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.findOrCreatePlace(address, zipCode), nil
+}
+
+func (r *memoryPlaceRepository) Exists(ctx context.Context, placeID int) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.findPlace(placeID) != nil, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"seicheese/internal/infrastructure/database"
+	"seicheese/models"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+type placeRepository struct {
+	db database.DB
+}
+
+// NewPlaceRepository はsqlboilerを使うPlaceRepositoryを作成
+func NewPlaceRepository(db database.DB) PlaceRepository {
+	return &placeRepository{db: db}
+}
+
+func (r *placeRepository) List(ctx context.Context) (models.PlaceSlice, error) {
+	return models.Places().All(ctx, r.db)
+}
+
+func (r *placeRepository) FindOrCreate(ctx context.Context, address, zipCode string) (*models.Place, error) {
+	return findOrCreatePlace(ctx, r.db, address, zipCode)
+}
+
+func (r *placeRepository) Exists(ctx context.Context, placeID int) (bool, error) {
+	return models.PlaceExists(ctx, r.db, placeID)
+}
+
+// findOrCreatePlace は場所を作成し、places.addressのUNIQUE制約に違反した場合は登録済みの場所を返す
+// 同じ住所への登録が同時に行われてもエラーにならず、同じ場所が返る
+// （INSERT IGNOREは重複以外のエラーも警告にして無視するため使わない）
+func findOrCreatePlace(ctx context.Context, exec boil.ContextExecutor, address, zipCode string) (*models.Place, error) {
+	place := &models.Place{
+		Address: address,
+		ZipCode: zipCode,
+	}
+	err := place.Insert(ctx, exec, boil.Infer())
+	if err == nil {
+		return place, nil
+	}
+	if !isDuplicateEntry(err) {
+		return nil, fmt.Errorf("failed to create place: %w", err)
+	}
+
+	existing, err := models.Places(models.PlaceWhere.Address.EQ(address)).One(ctx, exec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find existing place: %w", err)
+	}
+	return existing, nil
+}
@@ -0,0 +1,140 @@
+// Seicheese-Backend/src/internal/repository/repository.go
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"seicheese/internal/infrastructure/database"
+	"seicheese/internal/validation"
+	"seicheese/models"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ErrNotFound は対象のデータが存在しない場合のエラー
+var ErrNotFound = errors.New("repository: not found")
+
+// MySQLの重複キーのエラー番号（ER_DUP_ENTRY）
+const errNumberDuplicateEntry = 1062
+
+// isDuplicateEntry はerrがUNIQUE制約・主キーの重複によるエラーかどうかを返す
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == errNumberDuplicateEntry
+}
+
+// SeichiRepository は聖地の永続化
+type SeichiRepository interface {
+	// List は作品・場所を読み込んだ聖地を登録順に返す
+	List(ctx context.Context, limit, offset int) (models.SeichySlice, error)
+	ListByUser(ctx context.Context, userID uint) (models.SeichySlice, error)
+	// Create は住所に対応する場所を取得または作成し、聖地を登録する（同じトランザクションで行う）
+	Create(ctx context.Context, seichi *models.Seichy, address, zipCode string) error
+	Exists(ctx context.Context, seichiID int) (bool, error)
+	// Clusters は近い位置（約1km四方）にある聖地をまとめた件数を返す（2件以上のもののみ）
+	Clusters(ctx context.Context) ([]SeichiCluster, error)
+}
+
+// SeichiCluster はまとめられた聖地の中心位置と件数
+type SeichiCluster struct {
+	Latitude  float64
+	Longitude float64
+	Count     int
+}
+
+// ContentRepository は作品の永続化
+type ContentRepository interface {
+	Create(ctx context.Context, content *models.Content) error
+	// Search は作品名（英語名を含む）の部分一致で検索する
+	Search(ctx context.Context, query string) (models.ContentSlice, error)
+	Exists(ctx context.Context, contentID int) (bool, error)
+}
+
+// GenreRepository はジャンルの永続化
+type GenreRepository interface {
+	List(ctx context.Context) (models.GenreSlice, error)
+	// CountByIDs は指定したIDのうち存在するジャンルの件数を返す
+	CountByIDs(ctx context.Context, genreIDs []int) (int64, error)
+	Exists(ctx context.Context, genreID int) (bool, error)
+}
+
+// UserRepository はユーザーの永続化
+type UserRepository interface {
+	FindByID(ctx context.Context, userID uint) (*models.User, error)
+	FindByFirebaseID(ctx context.Context, firebaseID string) (*models.User, error)
+	// InsertIfNotExists はfirebase_idが同じユーザーがいなければ作成し、userに保存後の値を設定する
+	// 戻り値のboolは今回の呼び出しで作成されたかどうか
+	InsertIfNotExists(ctx context.Context, user *models.User) (bool, error)
+	// Update は指定した列のみ更新する（updated_atは自動で更新される）
+	Update(ctx context.Context, user *models.User, columns ...string) error
+	// DeleteAccount はユーザーと関連データを削除する
+	// 登録した聖地は残して登録者をNULLにし、チェックイン履歴・ポイントは削除する
+	DeleteAccount(ctx context.Context, user *models.User) error
+	// Points はユーザーのポイント（未作成の場合はnil）とポイント履歴を返す
+	Points(ctx context.Context, userID uint) (*models.Point, models.PointLogSlice, error)
+}
+
+// CheckinRepository はチェックイン履歴の永続化
+type CheckinRepository interface {
+	// ListByUser は新しい順に返す
+	ListByUser(ctx context.Context, userID uint) (models.CheckinLogSlice, error)
+	Create(ctx context.Context, checkin *models.CheckinLog) error
+}
+
+// PlaceRepository は場所の永続化
+type PlaceRepository interface {
+	List(ctx context.Context) (models.PlaceSlice, error)
+	// FindOrCreate は住所に対応する場所を取得し、存在しなければ作成する
+	// 既存の場所の場合、郵便番号は既存の値のまま
+	FindOrCreate(ctx context.Context, address, zipCode string) (*models.Place, error)
+	Exists(ctx context.Context, placeID int) (bool, error)
+}
+
+// Repositories はすべてのリポジトリをまとめたもの
+type Repositories struct {
+	Seichies SeichiRepository
+	Contents ContentRepository
+	Genres   GenreRepository
+	Users    UserRepository
+	Checkins CheckinRepository
+	Places   PlaceRepository
+}
+
+// NewSQLRepositories はsqlboilerでMySQLを使うリポジトリを作成（クエリごとにトレースのスパンを記録する）
+func NewSQLRepositories(sqlDB *sql.DB) *Repositories {
+	db := database.Trace(sqlDB)
+	return &Repositories{
+		Seichies: NewSeichiRepository(db),
+		Contents: NewContentRepository(db),
+		Genres:   NewGenreRepository(db),
+		Users:    NewUserRepository(db),
+		Checkins: NewCheckinRepository(db),
+		Places:   NewPlaceRepository(db),
+	}
+}
+
+// NewMemoryRepositories はメモリ上にデータを持つリポジトリを作成（テスト用）
+// 各リポジトリはstoreのデータを共有する
+func NewMemoryRepositories(store *MemoryStore) *Repositories {
+	return &Repositories{
+		Seichies: &memorySeichiRepository{store},
+		Contents: &memoryContentRepository{store},
+		Genres:   &memoryGenreRepository{store},
+		Users:    &memoryUserRepository{store},
+		Checkins: &memoryCheckinRepository{store},
+		Places:   &memoryPlaceRepository{store},
+	}
+}
+
+// ExistsCheckers はvalidationのexistsタグで使う存在確認の関数
+func (r *Repositories) ExistsCheckers() validation.ExistsCheckers {
+	return validation.ExistsCheckers{
+		"content": r.Contents.Exists,
+		"genre":   r.Genres.Exists,
+		"seichi":  r.Seichies.Exists,
+		"place":   r.Places.Exists,
+	}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"seicheese/internal/infrastructure/database"
+	"seicheese/models"
+
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+type seichiRepository struct {
+	db database.DB
+}
+
+// NewSeichiRepository はsqlboilerを使うSeichiRepositoryを作成
+func NewSeichiRepository(db database.DB) SeichiRepository {
+	return &seichiRepository{db: db}
+}
+
+func (r *seichiRepository) List(ctx context.Context, limit, offset int) (models.SeichySlice, error) {
+	return models.Seichies(
+		qm.Load(models.SeichyRels.Content),
+		qm.Load(models.SeichyRels.Place),
+		qm.OrderBy(models.SeichyColumns.SeichiID),
+		qm.Limit(limit),
+		qm.Offset(offset),
+	).All(ctx, r.db)
+}
+
+func (r *seichiRepository) ListByUser(ctx context.Context, userID uint) (models.SeichySlice, error) {
+	return models.Seichies(
+		models.SeichyWhere.UserID.EQ(null.UintFrom(userID)),
+		qm.OrderBy(models.SeichyColumns.SeichiID),
+	).All(ctx, r.db)
+}
+
+func (r *seichiRepository) Create(ctx context.Context, seichi *models.Seichy, address, zipCode string) error {
+	return database.WithTx(ctx, r.db, func(tx boil.ContextExecutor) error {
+		place, err := findOrCreatePlace(ctx, tx, address, zipCode)
+		if err != nil {
+			return err
+		}
+
+		seichi.PlaceID = place.PlaceID
+		if err := seichi.Insert(ctx, tx, boil.Infer()); err != nil {
+			return fmt.Errorf("failed to insert seichi: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *seichiRepository) Exists(ctx context.Context, seichiID int) (bool, error) {
+	return models.SeichyExists(ctx, r.db, seichiID)
+}
+
+func (r *seichiRepository) Clusters(ctx context.Context) ([]SeichiCluster, error) {
+	// SQLでグリッドベースのクラスタリングを実行
+	query := `
+	SELECT 
+		ROUND(AVG(CAST(latitude AS FLOAT)), 4) as lat,
+		ROUND(AVG(CAST(longitude AS FLOAT)), 4) as lng,
+		COUNT(*) as count
+	FROM seichies
+	GROUP BY 
+		FLOOR(CAST(latitude AS FLOAT) * 100),
+		FLOOR(CAST(longitude AS FLOAT) * 100)
+	HAVING count > 1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clusters []SeichiCluster
+	for rows.Next() {
+		var cluster SeichiCluster
+		if err := rows.Scan(&cluster.Latitude, &cluster.Longitude, &cluster.Count); err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, rows.Err()
+}
@@ -0,0 +1,448 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"seicheese/internal/infrastructure/database/dbtest"
+	"seicheese/internal/repository"
+	"seicheese/models"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// MySQLを使うテストはTEST_DB_DSNを設定するか、mysqldをインストールした環境でのみ実行される
+//
+//	TEST_DB_DSN='root:pass@tcp(127.0.0.1:3312)/' go test ./internal/repository
+func TestMain(m *testing.M) { dbtest.Main(m) }
+
+// sqlboilerのモデルとテーブルの対応（マイグレーション後のスキーマと比較する）
+var modelTables = map[string]interface{}{
+	models.TableNames.CheckinLogs:    models.CheckinLog{},
+	models.TableNames.Contents:       models.Content{},
+	models.TableNames.Genres:         models.Genre{},
+	models.TableNames.GooseDBVersion: models.GooseDBVersion{},
+	models.TableNames.Places:         models.Place{},
+	models.TableNames.PointLogs:      models.PointLog{},
+	models.TableNames.Points:         models.Point{},
+	models.TableNames.Seichies:       models.Seichy{},
+	models.TableNames.Users:          models.User{},
+}
+
+func TestMigrationFiles(t *testing.T) {
+	files, err := fs.Glob(dbtest.MigrationsFS(), "*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no migrations found")
+	}
+	for _, name := range files {
+		data, err := fs.ReadFile(dbtest.MigrationsFS(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, annotation := range []string{"-- +goose Up", "-- +goose Down"} {
+			if !strings.Contains(string(data), annotation) {
+				t.Errorf("%s: missing %q", name, annotation)
+			}
+		}
+	}
+}
+
+func TestMigrationsUpDown(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.New(t)
+	provider := dbtest.NewProvider(t, db)
+
+	if _, err := provider.Up(ctx); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	assertModelsMatchSchema(t, db)
+
+	if _, err := provider.DownTo(ctx, 0); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if tables := listTables(t, db); !reflect.DeepEqual(tables, []string{models.TableNames.GooseDBVersion}) {
+		t.Errorf("tables after down = %v, want only %s", tables, models.TableNames.GooseDBVersion)
+	}
+
+	// 一度戻したマイグレーションを再適用できること
+	if _, err := provider.Up(ctx); err != nil {
+		t.Fatalf("up again: %v", err)
+	}
+	assertModelsMatchSchema(t, db)
+}
+
+// assertModelsMatchSchema はモデルのboilタグとテーブルの列が一致することを確認する
+func assertModelsMatchSchema(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	tables := listTables(t, db)
+	for _, table := range tables {
+		if _, ok := modelTables[table]; !ok {
+			t.Errorf("table %s has no sqlboiler model", table)
+		}
+	}
+
+	for table, model := range modelTables {
+		var want []string
+		typ := reflect.TypeOf(model)
+		for i := 0; i < typ.NumField(); i++ {
+			if tag := typ.Field(i).Tag.Get("boil"); tag != "" && tag != "-" {
+				want = append(want, tag)
+			}
+		}
+		sort.Strings(want)
+
+		got := listColumns(t, db, table)
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("columns of %s = %v, model has %v", table, got, want)
+		}
+	}
+}
+
+func listTables(t *testing.T, db *sql.DB) []string {
+	t.Helper()
+	return queryStrings(t, db, "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() ORDER BY table_name")
+}
+
+func listColumns(t *testing.T, db *sql.DB, table string) []string {
+	t.Helper()
+	return queryStrings(t, db, "SELECT column_name FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ?", table)
+}
+
+func queryStrings(t *testing.T, db *sql.DB, query string, args ...interface{}) []string {
+	t.Helper()
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, v)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
+
+// fixtures はseedFixturesで投入したデータ
+type fixtures struct {
+	genre   *models.Genre
+	content *models.Content
+	place   *models.Place
+	user    *models.User
+	seichi  *models.Seichy
+}
+
+// seedFixtures はマイグレーション済みのデータベースに共通のデータを投入する
+func seedFixtures(t *testing.T, db *sql.DB) fixtures {
+	t.Helper()
+	ctx := context.Background()
+	createdAt := time.Date(2024, 11, 1, 9, 0, 0, 0, time.UTC)
+
+	f := fixtures{
+		genre: &models.Genre{
+			GenreName:   "アニメ",
+			GenreNameEn: null.StringFrom("Anime"),
+		},
+		place: &models.Place{
+			Address: "東京都新宿区須賀町5",
+			ZipCode: "160-0018",
+		},
+		user: &models.User{
+			FirebaseID:  "uid-registered",
+			DisplayName: null.StringFrom("聖地太郎"),
+		},
+	}
+	insert(t, f.genre.Insert(ctx, db, boil.Infer()))
+	insert(t, f.place.Insert(ctx, db, boil.Infer()))
+	insert(t, f.user.Insert(ctx, db, boil.Infer()))
+
+	f.content = &models.Content{
+		ContentName:   "君の名は。",
+		ContentNameEn: null.StringFrom("Your Name."),
+		GenreID:       f.genre.GenreID,
+	}
+	insert(t, f.content.Insert(ctx, db, boil.Infer()))
+
+	f.seichi = &models.Seichy{
+		UserID:     null.UintFrom(f.user.UserID),
+		SeichiName: "須賀神社",
+		Latitude:   types.NewDecimal(decimal.New(356851, 4)),
+		Longitude:  types.NewDecimal(decimal.New(1397224, 4)),
+		PlaceID:    f.place.PlaceID,
+		ContentID:  f.content.ContentID,
+	}
+	insert(t, f.seichi.Insert(ctx, db, boil.Infer()))
+
+	insert(t, (&models.CheckinLog{
+		UserID:    f.user.UserID,
+		SeichiID:  f.seichi.SeichiID,
+		CreatedAt: createdAt,
+	}).Insert(ctx, db, boil.Infer()))
+	insert(t, (&models.Point{
+		UserID:       f.user.UserID,
+		CurrentPoint: 10,
+	}).Insert(ctx, db, boil.Infer()))
+	insert(t, (&models.PointLog{
+		UserID:    f.user.UserID,
+		Point:     10,
+		CreatedAt: createdAt,
+	}).Insert(ctx, db, boil.Infer()))
+
+	return f
+}
+
+func insert(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+}
+
+func newSQLRepositories(t *testing.T) (*repository.Repositories, fixtures, *sql.DB) {
+	t.Helper()
+
+	db := dbtest.NewMigrated(t)
+	return repository.NewSQLRepositories(db), seedFixtures(t, db), db
+}
+
+func TestSQLSeichiRepository(t *testing.T) {
+	ctx := context.Background()
+	repos, f, _ := newSQLRepositories(t)
+
+	// 同じ住所の場所は再利用される
+	seichi := &models.Seichy{
+		UserID:     null.UintFrom(f.user.UserID),
+		SeichiName: "新宿区立須賀公園",
+		Latitude:   types.NewDecimal(decimal.New(356856, 4)),
+		Longitude:  types.NewDecimal(decimal.New(1397230, 4)),
+		ContentID:  f.content.ContentID,
+	}
+	if err := repos.Seichies.Create(ctx, seichi, f.place.Address, f.place.ZipCode); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if seichi.PlaceID != f.place.PlaceID {
+		t.Errorf("PlaceID = %d, want existing place %d", seichi.PlaceID, f.place.PlaceID)
+	}
+
+	seichies, err := repos.Seichies.List(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(seichies) != 2 {
+		t.Fatalf("List returned %d seichies, want 2", len(seichies))
+	}
+	if r := seichies[0].R; r == nil || r.Content == nil || r.Place == nil {
+		t.Errorf("List did not load content and place: %+v", r)
+	}
+
+	if seichies, err := repos.Seichies.List(ctx, 1, 1); err != nil || len(seichies) != 1 || seichies[0].SeichiID != seichi.SeichiID {
+		t.Errorf("List(1, 1) = %v, %v; want [%d]", seichies, err, seichi.SeichiID)
+	}
+
+	if byUser, err := repos.Seichies.ListByUser(ctx, f.user.UserID); err != nil || len(byUser) != 2 {
+		t.Errorf("ListByUser = %d seichies, %v; want 2", len(byUser), err)
+	}
+
+	if ok, err := repos.Seichies.Exists(ctx, seichi.SeichiID); err != nil || !ok {
+		t.Errorf("Exists(%d) = %v, %v; want true", seichi.SeichiID, ok, err)
+	}
+	if ok, err := repos.Seichies.Exists(ctx, 999999); err != nil || ok {
+		t.Errorf("Exists(999999) = %v, %v; want false", ok, err)
+	}
+
+	// 近接する2件は1つのクラスタにまとめられる
+	clusters, err := repos.Seichies.Clusters(ctx)
+	if err != nil {
+		t.Fatalf("Clusters: %v", err)
+	}
+	if len(clusters) != 1 || clusters[0].Count != 2 {
+		t.Errorf("Clusters = %+v, want one cluster of 2", clusters)
+	}
+}
+
+func TestSQLContentRepository(t *testing.T) {
+	ctx := context.Background()
+	repos, f, _ := newSQLRepositories(t)
+
+	content := &models.Content{ContentName: "天気の子", GenreID: f.genre.GenreID}
+	if err := repos.Contents.Create(ctx, content); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"君の", []int{f.content.ContentID}},
+		{"your", []int{f.content.ContentID}},
+		{"天気", []int{content.ContentID}},
+		{"存在しない", nil},
+	}
+	for _, tt := range tests {
+		contents, err := repos.Contents.Search(ctx, tt.query)
+		if err != nil {
+			t.Fatalf("Search(%q): %v", tt.query, err)
+		}
+		var got []int
+		for _, c := range contents {
+			got = append(got, c.ContentID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if ok, err := repos.Contents.Exists(ctx, content.ContentID); err != nil || !ok {
+		t.Errorf("Exists(%d) = %v, %v; want true", content.ContentID, ok, err)
+	}
+}
+
+func TestSQLGenreRepository(t *testing.T) {
+	ctx := context.Background()
+	repos, f, _ := newSQLRepositories(t)
+
+	genres, err := repos.Genres.List(ctx)
+	if err != nil || len(genres) != 1 || genres[0].GenreNameEn.String != "Anime" {
+		t.Errorf("List = %v, %v; want the seeded genre", genres, err)
+	}
+
+	count, err := repos.Genres.CountByIDs(ctx, []int{f.genre.GenreID, 999999})
+	if err != nil || count != 1 {
+		t.Errorf("CountByIDs = %d, %v; want 1", count, err)
+	}
+}
+
+func TestSQLUserRepository(t *testing.T) {
+	ctx := context.Background()
+	repos, f, db := newSQLRepositories(t)
+
+	// 既存ユーザーは作成されず、保存済みの値が読み込まれる
+	existing := &models.User{FirebaseID: f.user.FirebaseID}
+	created, err := repos.Users.InsertIfNotExists(ctx, existing)
+	if err != nil || created {
+		t.Fatalf("InsertIfNotExists(existing) = %v, %v; want false", created, err)
+	}
+	if existing.UserID != f.user.UserID {
+		t.Errorf("InsertIfNotExists(existing) loaded user %d, want %d", existing.UserID, f.user.UserID)
+	}
+
+	user := &models.User{FirebaseID: "uid-new"}
+	if created, err := repos.Users.InsertIfNotExists(ctx, user); err != nil || !created {
+		t.Fatalf("InsertIfNotExists(new) = %v, %v; want true", created, err)
+	}
+
+	// 重複以外のエラー（列の長さの超過など）は既存ユーザーとして扱わずにエラーを返す
+	tooLong := &models.User{FirebaseID: "uid-too-long", DisplayName: null.StringFrom(strings.Repeat("あ", 51))}
+	if created, err := repos.Users.InsertIfNotExists(ctx, tooLong); err == nil {
+		t.Errorf("InsertIfNotExists(too long display name) = %v, want error", created)
+	}
+
+	user.DisplayName = null.StringFrom("聖地花子")
+	if err := repos.Users.Update(ctx, user, models.UserColumns.DisplayName); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	found, err := repos.Users.FindByFirebaseID(ctx, "uid-new")
+	if err != nil || found.DisplayName.String != "聖地花子" {
+		t.Errorf("FindByFirebaseID after Update = %v, %v", found, err)
+	}
+
+	if _, err := repos.Users.FindByID(ctx, 999999); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("FindByID(999999) error = %v, want ErrNotFound", err)
+	}
+
+	point, logs, err := repos.Users.Points(ctx, f.user.UserID)
+	if err != nil || point == nil || point.CurrentPoint != 10 || len(logs) != 1 {
+		t.Errorf("Points = %v, %v, %v; want 10 points and 1 log", point, logs, err)
+	}
+
+	// 削除するとポイント・履歴は消え、聖地は匿名化される
+	if err := repos.Users.DeleteAccount(ctx, f.user); err != nil {
+		t.Fatalf("DeleteAccount: %v", err)
+	}
+	if _, err := repos.Users.FindByID(ctx, f.user.UserID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("FindByID after delete error = %v, want ErrNotFound", err)
+	}
+	seichi, err := models.FindSeichy(ctx, db, f.seichi.SeichiID)
+	if err != nil || seichi.UserID.Valid {
+		t.Errorf("seichi after delete = %v, %v; want anonymized", seichi, err)
+	}
+	if point, logs, err := repos.Users.Points(ctx, f.user.UserID); err != nil || point != nil || len(logs) != 0 {
+		t.Errorf("Points after delete = %v, %v, %v; want none", point, logs, err)
+	}
+	if checkins, err := repos.Checkins.ListByUser(ctx, f.user.UserID); err != nil || len(checkins) != 0 {
+		t.Errorf("checkins after delete = %v, %v; want none", checkins, err)
+	}
+}
+
+func TestSQLCheckinRepository(t *testing.T) {
+	ctx := context.Background()
+	repos, f, _ := newSQLRepositories(t)
+
+	latest := &models.CheckinLog{
+		UserID:    f.user.UserID,
+		SeichiID:  f.seichi.SeichiID,
+		CreatedAt: time.Date(2024, 11, 2, 9, 0, 0, 0, time.UTC),
+	}
+	if err := repos.Checkins.Create(ctx, latest); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	checkins, err := repos.Checkins.ListByUser(ctx, f.user.UserID)
+	if err != nil {
+		t.Fatalf("ListByUser: %v", err)
+	}
+	if len(checkins) != 2 || !checkins[0].CreatedAt.Equal(latest.CreatedAt) {
+		t.Errorf("ListByUser = %v, want 2 checkins newest first", checkins)
+	}
+}
+
+func TestSQLPlaceRepository(t *testing.T) {
+	ctx := context.Background()
+	repos, f, _ := newSQLRepositories(t)
+
+	place, err := repos.Places.FindOrCreate(ctx, f.place.Address, f.place.ZipCode)
+	if err != nil || place.PlaceID != f.place.PlaceID {
+		t.Errorf("FindOrCreate(existing) = %v, %v; want place %d", place, err, f.place.PlaceID)
+	}
+
+	place, err = repos.Places.FindOrCreate(ctx, "東京都新宿区須賀町6", "160-0018")
+	if err != nil || place.PlaceID == f.place.PlaceID {
+		t.Fatalf("FindOrCreate(new) = %v, %v; want a new place", place, err)
+	}
+
+	// 重複以外のエラー（列の長さの超過など）は既存の場所として扱わずにエラーを返す
+	if place, err := repos.Places.FindOrCreate(ctx, strings.Repeat("あ", 256), "160-0018"); err == nil {
+		t.Errorf("FindOrCreate(too long address) = %v, want error", place)
+	}
+
+	places, err := repos.Places.List(ctx)
+	if err != nil || len(places) != 2 {
+		t.Errorf("List = %d places, %v; want 2", len(places), err)
+	}
+	if ok, err := repos.Places.Exists(ctx, place.PlaceID); err != nil || !ok {
+		t.Errorf("Exists(%d) = %v, %v; want true", place.PlaceID, ok, err)
+	}
+}
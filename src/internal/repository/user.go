@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"seicheese/internal/infrastructure/database"
+	"seicheese/models"
+
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+type userRepository struct {
+	db database.DB
+}
+
+// NewUserRepository はsqlboilerを使うUserRepositoryを作成
+func NewUserRepository(db database.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) FindByID(ctx context.Context, userID uint) (*models.User, error) {
+	user, err := models.FindUser(ctx, r.db, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return user, err
+}
+
+func (r *userRepository) FindByFirebaseID(ctx context.Context, firebaseID string) (*models.User, error) {
+	user, err := models.Users(
+		models.UserWhere.FirebaseID.EQ(firebaseID),
+	).One(ctx, r.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return user, err
+}
+
+func (r *userRepository) InsertIfNotExists(ctx context.Context, user *models.User) (bool, error) {
+	// firebase_idのUNIQUE制約に違反した場合のみ登録済みのユーザーを読み込む
+	// （INSERT IGNOREは重複以外のエラーも警告にして無視するため使わない）
+	err := user.Insert(ctx, r.db, boil.Infer())
+	if err == nil {
+		return true, nil
+	}
+	if !isDuplicateEntry(err) {
+		return false, err
+	}
+
+	existing, err := r.FindByFirebaseID(ctx, user.FirebaseID)
+	if err != nil {
+		return false, fmt.Errorf("find existing user: %w", err)
+	}
+	*user = *existing
+	return false, nil
+}
+
+func (r *userRepository) Update(ctx context.Context, user *models.User, columns ...string) error {
+	columns = append(columns, models.UserColumns.UpdatedAt)
+	_, err := user.Update(ctx, r.db, boil.Whitelist(columns...))
+	return err
+}
+
+func (r *userRepository) DeleteAccount(ctx context.Context, user *models.User) error {
+	return database.WithTx(ctx, r.db, func(tx boil.ContextExecutor) error {
+		if _, err := models.Seichies(
+			models.SeichyWhere.UserID.EQ(null.UintFrom(user.UserID)),
+		).UpdateAll(ctx, tx, models.M{models.SeichyColumns.UserID: nil}); err != nil {
+			return fmt.Errorf("anonymize seichies: %w", err)
+		}
+		if _, err := models.CheckinLogs(models.CheckinLogWhere.UserID.EQ(user.UserID)).DeleteAll(ctx, tx); err != nil {
+			return fmt.Errorf("delete checkin logs: %w", err)
+		}
+		if _, err := models.PointLogs(models.PointLogWhere.UserID.EQ(user.UserID)).DeleteAll(ctx, tx); err != nil {
+			return fmt.Errorf("delete point logs: %w", err)
+		}
+		if _, err := models.Points(models.PointWhere.UserID.EQ(user.UserID)).DeleteAll(ctx, tx); err != nil {
+			return fmt.Errorf("delete points: %w", err)
+		}
+		if _, err := user.Delete(ctx, tx); err != nil {
+			return fmt.Errorf("delete user: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *userRepository) Points(ctx context.Context, userID uint) (*models.Point, models.PointLogSlice, error) {
+	point, err := models.FindPoint(ctx, r.db, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, err
+	}
+
+	logs, err := models.PointLogs(
+		models.PointLogWhere.UserID.EQ(userID),
+		qm.OrderBy(models.PointLogColumns.CreatedAt),
+	).All(ctx, r.db)
+	if err != nil {
+		return nil, nil, err
+	}
+	return point, logs, nil
+}
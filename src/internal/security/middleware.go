@@ -0,0 +1,78 @@
+package security
+
+import (
+	"net"
+
+	"seicheese/internal/appversion"
+	"seicheese/internal/logging"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// CORS は設定したオリジン・メソッドからのクロスオリジンリクエストを許可する
+// クライアントがリクエストIDと再試行までの秒数、推奨バージョンを読めるよう、X-Request-ID・Retry-After・X-App-Recommended-Versionを公開する
+func CORS(config *CORSConfig) echo.MiddlewareFunc {
+	return middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins: config.AllowOrigins,
+		AllowMethods: config.AllowMethods,
+		AllowHeaders: []string{
+			echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, logging.HeaderRequestID,
+			appversion.HeaderAppVersion, appversion.HeaderAppPlatform,
+		},
+		ExposeHeaders:    []string{logging.HeaderRequestID, echo.HeaderRetryAfter, appversion.HeaderRecommendedVersion},
+		AllowCredentials: config.AllowCredentials,
+		MaxAge:           int(config.MaxAge.Seconds()),
+	})
+}
+
+// Headers はセキュリティ関連のレスポンスヘッダーを付与する
+//   - X-Content-Type-Options: nosniff（Content-Typeの推測を禁止）
+//   - X-Frame-Options: DENY と frame-ancestors 'none'（フレームへの埋め込みを禁止）
+//   - Content-Security-Policy: default-src 'none'（JSONと画像だけを返すAPIのため何も読み込ませない）
+//   - Referrer-Policy: no-referrer
+//   - Strict-Transport-Security（HSTSMaxAgeが0より大きく、HTTPSのリクエストの場合のみ）
+func Headers(config *Config) echo.MiddlewareFunc {
+	return middleware.SecureWithConfig(middleware.SecureConfig{
+		XSSProtection:         "0",
+		ContentTypeNosniff:    "nosniff",
+		XFrameOptions:         "DENY",
+		HSTSMaxAge:            int(config.HSTSMaxAge.Seconds()),
+		ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+		ReferrerPolicy:        "no-referrer",
+	})
+}
+
+// IPExtractor はc.RealIP()がクライアントのIPアドレスを得る方法（レート制限などで使う）
+// TrustedProxiesがない場合は接続元のアドレスを使い、クライアントが送るX-Forwarded-ForとX-Real-IPは無視する
+// ある場合は、その範囲のプロキシから受けたX-Forwarded-Forだけを右からたどる
+func IPExtractor(config *Config) echo.IPExtractor {
+	if len(config.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, cidr := range config.TrustedProxies {
+		// 形式はValidateで検証済み
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			options = append(options, echo.TrustIPRange(ipNet))
+		}
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// BodyLimit はリクエストボディが上限を超えた場合に413を返す
+// UploadRoutesのルートにはUploadBodyLimit、それ以外にはBodyLimitを適用する
+// ルーティング後に判定するため、e.Preではなくe.Useで登録すること
+func BodyLimit(config *Config) echo.MiddlewareFunc {
+	standard := middleware.BodyLimit(config.BodyLimit)
+	upload := middleware.BodyLimit(config.UploadBodyLimit)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		standardNext, uploadNext := standard(next), upload(next)
+		return func(c echo.Context) error {
+			if UploadRoutes[routeKey(c)] {
+				return uploadNext(c)
+			}
+			return standardNext(c)
+		}
+	}
+}
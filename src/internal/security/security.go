@@ -0,0 +1,114 @@
+// Package security はCORS・セキュリティ関連のレスポンスヘッダー・リクエストボディのサイズ上限を設定する
+//
+// 環境ごとに許可するオリジンとメソッドを設定で切り替える（本番では管理画面などのオリジンだけを許可する）
+package security
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/bytes"
+)
+
+// CORSの設定構造体
+type CORSConfig struct {
+	// 許可するオリジン（"*"ですべて許可、例: https://admin.seicheese.jp）
+	AllowOrigins []string `yaml:"allow_origins"`
+	// 許可するメソッド
+	AllowMethods []string `yaml:"allow_methods"`
+	// Cookieなどの認証情報を含むリクエストを許可するか（AllowOriginsに"*"がある場合は使えない）
+	AllowCredentials bool `yaml:"allow_credentials"`
+	// プリフライトの結果をブラウザがキャッシュする時間
+	MaxAge time.Duration `yaml:"max_age"`
+}
+
+// CORSで許可できるメソッド
+var corsMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodHead:   true,
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// Validate はオリジンとメソッドの形式を検証する
+func (c *CORSConfig) Validate() error {
+	var errs []error
+	if len(c.AllowOrigins) == 0 {
+		errs = append(errs, errors.New("CORS_ALLOW_ORIGINS is required"))
+	}
+	for _, origin := range c.AllowOrigins {
+		if origin == "*" {
+			if c.AllowCredentials {
+				errs = append(errs, errors.New("CORS_ALLOW_CREDENTIALS cannot be used with CORS_ALLOW_ORIGINS=*"))
+			}
+			continue
+		}
+		if !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			errs = append(errs, fmt.Errorf("CORS_ALLOW_ORIGINS must be * or start with http:// or https://: %q", origin))
+		}
+	}
+	if len(c.AllowMethods) == 0 {
+		errs = append(errs, errors.New("CORS_ALLOW_METHODS is required"))
+	}
+	for _, method := range c.AllowMethods {
+		if !corsMethods[method] {
+			errs = append(errs, fmt.Errorf("unsupported CORS_ALLOW_METHODS: %q", method))
+		}
+	}
+	if c.MaxAge < 0 {
+		errs = append(errs, fmt.Errorf("CORS_MAX_AGE must not be negative: %s", c.MaxAge))
+	}
+	return errors.Join(errs...)
+}
+
+// セキュリティヘッダーとボディサイズの設定構造体
+type Config struct {
+	// Strict-Transport-Securityのmax-age（0の場合は送らない、HTTPSのリクエストにだけ付与する）
+	HSTSMaxAge time.Duration `yaml:"hsts_max_age"`
+	// リクエストボディの上限（例: 1M）
+	BodyLimit string `yaml:"body_limit"`
+	// 画像のアップロードなど、UploadRoutesに指定したルートのリクエストボディの上限（例: 6M）
+	UploadBodyLimit string `yaml:"upload_body_limit"`
+	// X-Forwarded-Forを付けるプロキシ（ロードバランサーなど）のアドレス範囲（CIDR、例: 10.0.0.0/8）
+	// 空の場合はX-Forwarded-Forを信頼せず、接続元のアドレスをクライアントのIPアドレスとする
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// Validate はサイズとアドレス範囲の形式を検証する
+func (c *Config) Validate() error {
+	var errs []error
+	for _, cidr := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errs = append(errs, fmt.Errorf("TRUSTED_PROXIES must be CIDR such as 10.0.0.0/8: %q", cidr))
+		}
+	}
+	if c.HSTSMaxAge < 0 {
+		errs = append(errs, fmt.Errorf("HSTS_MAX_AGE must not be negative: %s", c.HSTSMaxAge))
+	}
+	for _, limit := range []struct{ name, value string }{
+		{"BODY_LIMIT", c.BodyLimit},
+		{"UPLOAD_BODY_LIMIT", c.UploadBodyLimit},
+	} {
+		if size, err := bytes.Parse(limit.value); err != nil || size <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be a size such as 1M: %q", limit.name, limit.value))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// UploadRoutes はUploadBodyLimitを適用するルート（"METHOD /path"）
+var UploadRoutes = map[string]bool{
+	"PUT /api/users/me/avatar": true,
+}
+
+// routeKey はルートを"METHOD /path"の形式で返す（ルーティング後のミドルウェアで使う）
+func routeKey(c echo.Context) string {
+	return c.Request().Method + " " + c.Path()
+}
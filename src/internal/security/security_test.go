@@ -0,0 +1,211 @@
+package security_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"seicheese/internal/security"
+
+	"github.com/labstack/echo/v4"
+)
+
+func newServer(cors *security.CORSConfig, config *security.Config) *echo.Echo {
+	e := echo.New()
+	e.Use(security.CORS(cors))
+	e.Use(security.Headers(config))
+	e.Use(security.BodyLimit(config))
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+	e.GET("/api/seichies", ok)
+	e.POST("/api/seichi/register", ok)
+	e.PUT("/api/users/me/avatar", ok)
+	e.DELETE("/api/users/me", ok)
+	return e
+}
+
+func defaultConfigs() (*security.CORSConfig, *security.Config) {
+	return &security.CORSConfig{
+		AllowOrigins: []string{"https://admin.seicheese.jp"},
+		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+		MaxAge:       10 * time.Minute,
+	}, &security.Config{
+		HSTSMaxAge:      365 * 24 * time.Hour,
+		BodyLimit:       "1K",
+		UploadBodyLimit: "4K",
+	}
+}
+
+func serve(e *echo.Echo, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORS(t *testing.T) {
+	e := newServer(defaultConfigs())
+
+	tests := []struct {
+		name       string
+		origin     string
+		method     string
+		wantOrigin string
+	}{
+		{"allowed origin", "https://admin.seicheese.jp", http.MethodPut, "https://admin.seicheese.jp"},
+		{"allowed origin DELETE", "https://admin.seicheese.jp", http.MethodDelete, "https://admin.seicheese.jp"},
+		{"other origin", "https://evil.example.com", http.MethodPut, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, "/api/users/me/avatar", nil)
+			req.Header.Set(echo.HeaderOrigin, tt.origin)
+			req.Header.Set(echo.HeaderAccessControlRequestMethod, tt.method)
+			rec := serve(e, req)
+
+			if got := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if tt.wantOrigin == "" {
+				return
+			}
+			if methods := rec.Header().Get(echo.HeaderAccessControlAllowMethods); !strings.Contains(methods, tt.method) {
+				t.Errorf("Access-Control-Allow-Methods = %q, want %s", methods, tt.method)
+			}
+			if maxAge := rec.Header().Get(echo.HeaderAccessControlMaxAge); maxAge != "600" {
+				t.Errorf("Access-Control-Max-Age = %q, want 600", maxAge)
+			}
+		})
+	}
+
+	// 実際のリクエストではリクエストIDを読めるようにする
+	req := httptest.NewRequest(http.MethodGet, "/api/seichies", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://admin.seicheese.jp")
+	rec := serve(e, req)
+	if exposed := rec.Header().Get(echo.HeaderAccessControlExposeHeaders); !strings.Contains(exposed, "X-Request-Id") && !strings.Contains(exposed, "X-Request-ID") {
+		t.Errorf("Access-Control-Expose-Headers = %q, want X-Request-ID", exposed)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	e := newServer(defaultConfigs())
+
+	rec := serve(e, httptest.NewRequest(http.MethodGet, "/api/seichies", nil))
+	for name, want := range map[string]string{
+		echo.HeaderXContentTypeOptions:     "nosniff",
+		echo.HeaderXFrameOptions:           "DENY",
+		echo.HeaderContentSecurityPolicy:   "default-src 'none'; frame-ancestors 'none'",
+		echo.HeaderReferrerPolicy:          "no-referrer",
+		echo.HeaderStrictTransportSecurity: "",
+		echo.HeaderXXSSProtection:          "0",
+	} {
+		if got := rec.Header().Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	// HSTSはHTTPSのリクエスト（TLS終端のプロキシ経由を含む）にだけ付与する
+	req := httptest.NewRequest(http.MethodGet, "/api/seichies", nil)
+	req.Header.Set(echo.HeaderXForwardedProto, "https")
+	rec = serve(e, req)
+	if got := rec.Header().Get(echo.HeaderStrictTransportSecurity); got != "max-age=31536000; includeSubdomains" {
+		t.Errorf("Strict-Transport-Security = %q, want max-age=31536000; includeSubdomains", got)
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	e := newServer(defaultConfigs())
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		size   int
+		want   int
+	}{
+		{"within limit", http.MethodPost, "/api/seichi/register", 1000, http.StatusNoContent},
+		{"over limit", http.MethodPost, "/api/seichi/register", 1001, http.StatusRequestEntityTooLarge},
+		{"upload within upload limit", http.MethodPut, "/api/users/me/avatar", 4000, http.StatusNoContent},
+		{"upload over upload limit", http.MethodPut, "/api/users/me/avatar", 4001, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(strings.Repeat("a", tt.size)))
+			if rec := serve(e, req); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestIPExtractor(t *testing.T) {
+	tests := []struct {
+		name       string
+		trusted    []string
+		remoteAddr string
+		want       string
+	}{
+		{"direct ignores spoofed headers", nil, "198.51.100.7:1234", "198.51.100.7"},
+		{"trusted proxy", []string{"10.0.0.0/8"}, "10.0.0.2:1234", "203.0.113.5"},
+		{"untrusted proxy", []string{"10.0.0.0/8"}, "198.51.100.7:1234", "198.51.100.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/seichies", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set(echo.HeaderXForwardedFor, "192.0.2.99, 203.0.113.5")
+			req.Header.Set(echo.HeaderXRealIP, "192.0.2.100")
+			if got := security.IPExtractor(&security.Config{TrustedProxies: tt.trusted})(req); got != tt.want {
+				t.Errorf("IP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cors *security.CORSConfig, config *security.Config)
+		wantErr string
+	}{
+		{"valid", func(*security.CORSConfig, *security.Config) {}, ""},
+		{"any origin", func(cors *security.CORSConfig, _ *security.Config) { cors.AllowOrigins = []string{"*"} }, ""},
+		{"no origins", func(cors *security.CORSConfig, _ *security.Config) { cors.AllowOrigins = nil }, "CORS_ALLOW_ORIGINS is required"},
+		{"origin without scheme", func(cors *security.CORSConfig, _ *security.Config) {
+			cors.AllowOrigins = []string{"admin.seicheese.jp"}
+		}, "CORS_ALLOW_ORIGINS must be"},
+		{"credentials with any origin", func(cors *security.CORSConfig, _ *security.Config) {
+			cors.AllowOrigins = []string{"*"}
+			cors.AllowCredentials = true
+		}, "CORS_ALLOW_CREDENTIALS"},
+		{"unknown method", func(cors *security.CORSConfig, _ *security.Config) {
+			cors.AllowMethods = []string{"get"}
+		}, "unsupported CORS_ALLOW_METHODS"},
+		{"negative max age", func(cors *security.CORSConfig, _ *security.Config) { cors.MaxAge = -time.Second }, "CORS_MAX_AGE"},
+		{"negative hsts max age", func(_ *security.CORSConfig, config *security.Config) { config.HSTSMaxAge = -time.Second }, "HSTS_MAX_AGE"},
+		{"invalid body limit", func(_ *security.CORSConfig, config *security.Config) { config.BodyLimit = "" }, "BODY_LIMIT must be a size"},
+		{"invalid upload body limit", func(_ *security.CORSConfig, config *security.Config) {
+			config.UploadBodyLimit = "5 megabytes"
+		}, "UPLOAD_BODY_LIMIT must be a size"},
+		{"trusted proxy without prefix length", func(_ *security.CORSConfig, config *security.Config) {
+			config.TrustedProxies = []string{"10.0.0.1"}
+		}, "TRUSTED_PROXIES must be CIDR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cors, config := defaultConfigs()
+			tt.modify(cors, config)
+			err := errors.Join(cors.Validate(), config.Validate())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
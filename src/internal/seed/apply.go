@@ -0,0 +1,189 @@
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"seicheese/internal/infrastructure/database"
+	"seicheese/models"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// Apply はフィクスチャを1つのトランザクションで投入する（途中で失敗した場合は何も反映されない）
+// 聖地は登録者なし（user_id=NULL）として登録する
+func Apply(ctx context.Context, db *sql.DB, fixture *Fixture) (Result, error) {
+	var result Result
+	if err := fixture.Validate(); err != nil {
+		return result, err
+	}
+
+	err := database.WithTx(ctx, db, func(tx boil.ContextExecutor) error {
+		// 失敗時に途中までの件数を返さないよう、成功した場合のみresultに反映する
+		var r Result
+		for _, g := range fixture.Genres {
+			if err := upsertGenre(ctx, tx, g, &r.Genres); err != nil {
+				return fmt.Errorf("genre %q: %w", g.Name, err)
+			}
+		}
+		for _, c := range fixture.Contents {
+			if err := upsertContent(ctx, tx, c, &r.Contents); err != nil {
+				return fmt.Errorf("content %q: %w", c.Name, err)
+			}
+		}
+		for _, s := range fixture.Seichies {
+			if err := upsertSeichi(ctx, tx, s, &r); err != nil {
+				return fmt.Errorf("seichi %q: %w", s.Name, err)
+			}
+		}
+		result = r
+		return nil
+	})
+	return result, err
+}
+
+func upsertGenre(ctx context.Context, exec boil.ContextExecutor, g Genre, count *Count) error {
+	genre, err := models.Genres(models.GenreWhere.GenreName.EQ(g.Name)).One(ctx, exec)
+	if errors.Is(err, sql.ErrNoRows) {
+		genre = &models.Genre{GenreName: g.Name, GenreNameEn: optional(g.NameEn)}
+		count.add(true, false)
+		return genre.Insert(ctx, exec, boil.Infer())
+	}
+	if err != nil {
+		return err
+	}
+
+	if genre.GenreNameEn == optional(g.NameEn) {
+		count.add(false, false)
+		return nil
+	}
+	genre.GenreNameEn = optional(g.NameEn)
+	count.add(false, true)
+	_, err = genre.Update(ctx, exec, boil.Whitelist(models.GenreColumns.GenreNameEn))
+	return err
+}
+
+func upsertContent(ctx context.Context, exec boil.ContextExecutor, c Content, count *Count) error {
+	genre, err := models.Genres(models.GenreWhere.GenreName.EQ(c.Genre)).One(ctx, exec)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("genre %q not found", c.Genre)
+	}
+	if err != nil {
+		return err
+	}
+
+	content, err := models.Contents(models.ContentWhere.ContentName.EQ(c.Name)).One(ctx, exec)
+	if errors.Is(err, sql.ErrNoRows) {
+		content = &models.Content{ContentName: c.Name, ContentNameEn: optional(c.NameEn), GenreID: genre.GenreID}
+		count.add(true, false)
+		return content.Insert(ctx, exec, boil.Infer())
+	}
+	if err != nil {
+		return err
+	}
+
+	if content.ContentNameEn == optional(c.NameEn) && content.GenreID == genre.GenreID {
+		count.add(false, false)
+		return nil
+	}
+	content.ContentNameEn = optional(c.NameEn)
+	content.GenreID = genre.GenreID
+	count.add(false, true)
+	_, err = content.Update(ctx, exec, boil.Whitelist(models.ContentColumns.ContentNameEn, models.ContentColumns.GenreID))
+	return err
+}
+
+func upsertPlace(ctx context.Context, exec boil.ContextExecutor, address, zipCode string, count *Count) (*models.Place, error) {
+	place, err := models.Places(models.PlaceWhere.Address.EQ(address)).One(ctx, exec)
+	if errors.Is(err, sql.ErrNoRows) {
+		place = &models.Place{Address: address, ZipCode: zipCode}
+		count.add(true, false)
+		return place, place.Insert(ctx, exec, boil.Infer())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if place.ZipCode == zipCode {
+		count.add(false, false)
+		return place, nil
+	}
+	place.ZipCode = zipCode
+	count.add(false, true)
+	_, err = place.Update(ctx, exec, boil.Whitelist(models.PlaceColumns.ZipCode))
+	return place, err
+}
+
+func upsertSeichi(ctx context.Context, exec boil.ContextExecutor, s Seichi, result *Result) error {
+	content, err := models.Contents(models.ContentWhere.ContentName.EQ(s.Content)).One(ctx, exec)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("content %q not found", s.Content)
+	}
+	if err != nil {
+		return err
+	}
+
+	place, err := upsertPlace(ctx, exec, s.Address, s.ZipCode, &result.Places)
+	if err != nil {
+		return fmt.Errorf("place %q: %w", s.Address, err)
+	}
+
+	latitude, longitude := toDecimal(s.Latitude), toDecimal(s.Longitude)
+	seichi, err := models.Seichies(
+		models.SeichyWhere.SeichiName.EQ(s.Name),
+		models.SeichyWhere.ContentID.EQ(content.ContentID),
+	).One(ctx, exec)
+	if errors.Is(err, sql.ErrNoRows) {
+		seichi = &models.Seichy{
+			SeichiName: s.Name,
+			Comment:    optional(s.Comment),
+			Latitude:   latitude,
+			Longitude:  longitude,
+			PlaceID:    place.PlaceID,
+			ContentID:  content.ContentID,
+		}
+		result.Seichies.add(true, false)
+		return seichi.Insert(ctx, exec, boil.Infer())
+	}
+	if err != nil {
+		return err
+	}
+
+	if seichi.Comment == optional(s.Comment) &&
+		seichi.Latitude.Cmp(latitude.Big) == 0 &&
+		seichi.Longitude.Cmp(longitude.Big) == 0 &&
+		seichi.PlaceID == place.PlaceID {
+		result.Seichies.add(false, false)
+		return nil
+	}
+	seichi.Comment = optional(s.Comment)
+	seichi.Latitude = latitude
+	seichi.Longitude = longitude
+	seichi.PlaceID = place.PlaceID
+	result.Seichies.add(false, true)
+	_, err = seichi.Update(ctx, exec, boil.Whitelist(
+		models.SeichyColumns.Comment,
+		models.SeichyColumns.Latitude,
+		models.SeichyColumns.Longitude,
+		models.SeichyColumns.PlaceID,
+	))
+	return err
+}
+
+// optional は空文字をNULLとして扱う
+func optional(s string) null.String {
+	return null.NewString(s, s != "")
+}
+
+// toDecimal はフィクスチャに書かれた桁のままDecimalに変換する
+// （SetFloat64では2進数の誤差を含み、DBの値との比較が一致しなくなる）
+func toDecimal(f float64) types.Decimal {
+	d, _ := new(decimal.Big).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	return types.NewDecimal(d)
+}
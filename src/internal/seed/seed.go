@@ -0,0 +1,238 @@
+// Package seed はYAML/JSONのフィクスチャからジャンル・作品・聖地の初期データを投入する
+//
+// 各データは自然キー（ジャンル名・作品名・住所・聖地名と作品の組み合わせ）で既存のデータと照合し、
+// 存在しなければ作成、内容が異なれば更新する。同じフィクスチャを何度適用しても結果は変わらない
+package seed
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture は投入するデータ
+// 作品はジャンルを、聖地は作品をそれぞれ名前で参照する（同じフィクスチャ内でもDBの既存データでもよい）
+type Fixture struct {
+	Genres   []Genre   `yaml:"genres" json:"genres"`
+	Contents []Content `yaml:"contents" json:"contents"`
+	Seichies []Seichi  `yaml:"seichies" json:"seichies"`
+}
+
+// Genre はジャンル（nameで照合する）
+type Genre struct {
+	Name   string `yaml:"name" json:"name"`
+	NameEn string `yaml:"name_en" json:"name_en"`
+}
+
+// Content は作品（nameで照合する）
+type Content struct {
+	Name   string `yaml:"name" json:"name"`
+	NameEn string `yaml:"name_en" json:"name_en"`
+	Genre  string `yaml:"genre" json:"genre"`
+}
+
+// Seichi は聖地（nameとcontentの組み合わせで照合する）
+// 場所はaddressで照合し、存在しなければ作成する
+type Seichi struct {
+	Name      string  `yaml:"name" json:"name"`
+	Comment   string  `yaml:"comment" json:"comment"`
+	Latitude  float64 `yaml:"latitude" json:"latitude"`
+	Longitude float64 `yaml:"longitude" json:"longitude"`
+	Address   string  `yaml:"address" json:"address"`
+	ZipCode   string  `yaml:"zip_code" json:"zip_code"`
+	Content   string  `yaml:"content" json:"content"`
+}
+
+// Merge はotherのデータを後ろに追加する
+func (f *Fixture) Merge(other *Fixture) {
+	f.Genres = append(f.Genres, other.Genres...)
+	f.Contents = append(f.Contents, other.Contents...)
+	f.Seichies = append(f.Seichies, other.Seichies...)
+}
+
+// Validate は必須項目と緯度・経度の範囲を検証する
+// 名前による参照はDBの既存データも対象になるため、Applyで解決する
+func (f *Fixture) Validate() error {
+	var errs []error
+	for i, g := range f.Genres {
+		if g.Name == "" {
+			errs = append(errs, fmt.Errorf("genres[%d]: name is required", i))
+		}
+	}
+	for i, c := range f.Contents {
+		if c.Name == "" {
+			errs = append(errs, fmt.Errorf("contents[%d]: name is required", i))
+		}
+		if c.Genre == "" {
+			errs = append(errs, fmt.Errorf("contents[%d]: genre is required", i))
+		}
+	}
+	for i, s := range f.Seichies {
+		if s.Name == "" {
+			errs = append(errs, fmt.Errorf("seichies[%d]: name is required", i))
+		}
+		if s.Content == "" {
+			errs = append(errs, fmt.Errorf("seichies[%d]: content is required", i))
+		}
+		if s.Address == "" {
+			errs = append(errs, fmt.Errorf("seichies[%d]: address is required", i))
+		}
+		if s.Latitude < -90 || s.Latitude > 90 {
+			errs = append(errs, fmt.Errorf("seichies[%d]: latitude %v is out of range", i, s.Latitude))
+		}
+		if s.Longitude < -180 || s.Longitude > 180 {
+			errs = append(errs, fmt.Errorf("seichies[%d]: longitude %v is out of range", i, s.Longitude))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Decode はフィクスチャを読み込む（formatは"yaml"または"json"）
+// 未知の項目はエラーにする
+func Decode(r io.Reader, format string) (*Fixture, error) {
+	fixture := &Fixture{}
+	switch format {
+	case "yaml":
+		decoder := yaml.NewDecoder(r)
+		decoder.KnownFields(true)
+		// 空のファイルは空のフィクスチャとして扱う
+		if err := decoder.Decode(fixture); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	case "json":
+		decoder := json.NewDecoder(r)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(fixture); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported fixture format %q", format)
+	}
+	return fixture, nil
+}
+
+// formatOf は拡張子からフィクスチャの形式を返す（対象外のファイルは空文字）
+func formatOf(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json":
+		return "json"
+	}
+	return ""
+}
+
+// LoadFS はfsysの直下にあるYAML/JSONファイルをファイル名順に読み込んでまとめる
+func LoadFS(fsys fs.FS) (*Fixture, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &Fixture{}
+	for _, entry := range entries {
+		if entry.IsDir() || formatOf(entry.Name()) == "" {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		f, err := Decode(bytes.NewReader(data), formatOf(entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		fixture.Merge(f)
+	}
+	return fixture, nil
+}
+
+// Load は指定したファイルまたはディレクトリ（直下のYAML/JSONファイル）を順に読み込んでまとめる
+func Load(paths ...string) (*Fixture, error) {
+	fixture := &Fixture{}
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+
+		var f *Fixture
+		if info.IsDir() {
+			f, err = LoadFS(os.DirFS(p))
+		} else {
+			f, err = loadFile(p)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Clean(p), err)
+		}
+		fixture.Merge(f)
+	}
+	return fixture, nil
+}
+
+func loadFile(name string) (*Fixture, error) {
+	format := formatOf(name)
+	if format == "" {
+		return nil, fmt.Errorf("unsupported fixture file (expected .yaml, .yml or .json)")
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Decode(file, format)
+}
+
+// Result は投入結果の件数
+type Result struct {
+	Genres   Count
+	Contents Count
+	Places   Count
+	Seichies Count
+}
+
+// Count はテーブルごとの件数
+type Count struct {
+	Inserted  int
+	Updated   int
+	Unchanged int
+}
+
+func (c *Count) add(inserted, updated bool) {
+	switch {
+	case inserted:
+		c.Inserted++
+	case updated:
+		c.Updated++
+	default:
+		c.Unchanged++
+	}
+}
+
+// WriteTo は投入結果を表形式で出力する
+func (r Result) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%-10s %8s %8s %9s\n", "Table", "Inserted", "Updated", "Unchanged")
+	rows := []struct {
+		name  string
+		count Count
+	}{
+		{"genres", r.Genres},
+		{"contents", r.Contents},
+		{"places", r.Places},
+		{"seichies", r.Seichies},
+	}
+	for _, row := range rows {
+		fmt.Fprintf(&buf, "%-10s %8d %8d %9d\n", row.name, row.count.Inserted, row.count.Updated, row.count.Unchanged)
+	}
+	return buf.WriteTo(w)
+}
@@ -0,0 +1,216 @@
+package seed_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	schema "seicheese/database"
+	"seicheese/internal/infrastructure/database/dbtest"
+	"seicheese/internal/seed"
+	"seicheese/models"
+)
+
+func TestMain(m *testing.M) { dbtest.Main(m) }
+
+const yamlFixture = `
+genres:
+  - name: アニメ
+    name_en: Anime
+contents:
+  - name: 作品A
+    genre: アニメ
+seichies:
+  - name: 聖地A
+    latitude: 35.6858
+    longitude: 139.7224
+    address: 東京都新宿区須賀町5
+    zip_code: "160-0018"
+    content: 作品A
+`
+
+const jsonFixture = `{
+  "genres": [{"name": "アニメ", "name_en": "Anime"}],
+  "contents": [{"name": "作品A", "genre": "アニメ"}],
+  "seichies": [{
+    "name": "聖地A",
+    "latitude": 35.6858,
+    "longitude": 139.7224,
+    "address": "東京都新宿区須賀町5",
+    "zip_code": "160-0018",
+    "content": "作品A"
+  }]
+}`
+
+func TestDecode(t *testing.T) {
+	fromYAML, err := seed.Decode(strings.NewReader(yamlFixture), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := seed.Decode(strings.NewReader(jsonFixture), "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Errorf("YAML and JSON fixtures differ:\n%+v\n%+v", fromYAML, fromJSON)
+	}
+
+	if f, err := seed.Decode(strings.NewReader(""), "yaml"); err != nil || len(f.Genres) != 0 {
+		t.Errorf("empty YAML = %+v, %v; want empty fixture", f, err)
+	}
+
+	for format, input := range map[string]string{
+		"yaml": "genres:\n  - name: アニメ\n    color: red\n",
+		"json": `{"genres": [{"name": "アニメ", "color": "red"}]}`,
+	} {
+		if _, err := seed.Decode(strings.NewReader(input), format); err == nil {
+			t.Errorf("%s with unknown field: expected error", format)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	fixture := &seed.Fixture{
+		Contents: []seed.Content{{Name: "作品A"}},
+		Seichies: []seed.Seichi{{Name: "聖地A", Content: "作品A", Address: "住所", Latitude: 91}},
+	}
+	err := fixture.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{"contents[0]: genre is required", "seichies[0]: latitude 91 is out of range"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	// ファイル名順に読み込まれる（ジャンル → 作品・聖地）
+	files := map[string]string{
+		"01_genres.json": `{"genres": [{"name": "アニメ"}]}`,
+		"02_more.yml":    "genres:\n  - name: 漫画\n",
+		"README.md":      "# 対象外",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	extra := filepath.Join(t.TempDir(), "extra.yaml")
+	if err := os.WriteFile(extra, []byte("genres:\n  - name: 映画\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fixture, err := seed.Load(dir, extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, g := range fixture.Genres {
+		names = append(names, g.Name)
+	}
+	if want := []string{"アニメ", "漫画", "映画"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("genres = %v, want %v", names, want)
+	}
+
+	if _, err := seed.Load(filepath.Join(dir, "README.md")); err == nil {
+		t.Error("loading a non-fixture file: expected error")
+	}
+}
+
+func TestEmbeddedSeeds(t *testing.T) {
+	fixture, err := seed.LoadFS(schema.Seeds())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fixture.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(fixture.Genres) == 0 || len(fixture.Contents) == 0 || len(fixture.Seichies) == 0 {
+		t.Errorf("embedded seeds should contain genres, contents and seichies: %+v", fixture)
+	}
+}
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.NewMigrated(t)
+
+	fixture, err := seed.LoadFS(schema.Seeds())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := seed.Apply(ctx, db, fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Genres.Inserted != len(fixture.Genres) || result.Contents.Inserted != len(fixture.Contents) || result.Seichies.Inserted != len(fixture.Seichies) {
+		t.Errorf("first apply = %+v, want everything inserted", result)
+	}
+
+	// 2回目は何も変わらない
+	result, err = seed.Apply(ctx, db, fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := seed.Result{
+		Genres:   seed.Count{Unchanged: len(fixture.Genres)},
+		Contents: seed.Count{Unchanged: len(fixture.Contents)},
+		Places:   seed.Count{Unchanged: len(fixture.Seichies)},
+		Seichies: seed.Count{Unchanged: len(fixture.Seichies)},
+	}
+	if result != want {
+		t.Errorf("second apply = %+v, want %+v", result, want)
+	}
+	assertCount(t, db, models.TableNames.Seichies, int64(len(fixture.Seichies)))
+
+	// 自然キーが同じデータは更新される
+	changed := &seed.Fixture{Seichies: []seed.Seichi{fixture.Seichies[0]}}
+	changed.Seichies[0].Comment = "更新したコメント"
+	result, err = seed.Apply(ctx, db, changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Seichies != (seed.Count{Updated: 1}) {
+		t.Errorf("apply with changed comment = %+v, want 1 updated seichi", result.Seichies)
+	}
+	seichi, err := models.Seichies(models.SeichyWhere.SeichiName.EQ(fixture.Seichies[0].Name)).One(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seichi.Comment.String != "更新したコメント" || seichi.UserID.Valid {
+		t.Errorf("seichi = %+v, want updated comment and no user", seichi)
+	}
+}
+
+func TestApplyRollsBackOnUnknownReference(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.NewMigrated(t)
+
+	fixture := &seed.Fixture{
+		Genres:   []seed.Genre{{Name: "アニメ"}},
+		Contents: []seed.Content{{Name: "作品A", Genre: "存在しないジャンル"}},
+	}
+	_, err := seed.Apply(ctx, db, fixture)
+	if err == nil || !strings.Contains(err.Error(), `genre "存在しないジャンル" not found`) {
+		t.Fatalf("Apply error = %v, want unknown genre", err)
+	}
+	assertCount(t, db, models.TableNames.Genres, 0)
+}
+
+func assertCount(t *testing.T, db *sql.DB, table string, want int64) {
+	t.Helper()
+	var got int64
+	if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("%s has %d rows, want %d", table, got, want)
+	}
+}
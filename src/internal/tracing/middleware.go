@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"net/http"
+
+	"seicheese/internal/logging"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "seicheese/internal/tracing"
+
+// Middleware はリクエストごとにサーバースパンを開始し、contextに設定する
+// スパン名はルート（/api/users/:id などの登録時のパス）で、traceparentヘッダーがあれば上流のトレースを引き継ぐ
+// エラーはここでHTTPErrorHandlerに渡し、確定したステータスを記録する
+func Middleware() echo.MiddlewareFunc {
+	tracer := otel.Tracer(tracerName)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			route := c.Path()
+			ctx, span := tracer.Start(ctx, req.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(req.Method),
+					semconv.HTTPRoute(route),
+					semconv.URLPath(req.URL.Path),
+				),
+			)
+			defer span.End()
+			if requestID := logging.RequestIDFromContext(ctx); requestID != "" {
+				span.SetAttributes(attribute.String("request_id", requestID))
+			}
+			c.SetRequest(req.WithContext(ctx))
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if status >= 500 {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Package tracing はOpenTelemetryのトレースを設定する
+//
+// HTTPリクエスト・SQLクエリ・トークン検証・Geocoding APIの呼び出しをスパンとして記録し、
+// 設定に応じてOTLPまたは標準出力に送る（無効の場合はスパンを記録しない）
+// 各パッケージはotel.Tracerでグローバルのプロバイダーからトレーサーを取得する
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"seicheese/internal/buildinfo"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// ServiceName はトレースに記録するサービス名
+const ServiceName = "seicheese"
+
+// エクスポーター
+const (
+	// ExporterNone はトレースを記録しない
+	ExporterNone = "none"
+	// ExporterStdout は標準出力にJSONで書き出す（ローカル開発用）
+	ExporterStdout = "stdout"
+	// ExporterOTLP はOTLP（HTTP）でコレクターに送る
+	ExporterOTLP = "otlp"
+)
+
+// トレースの設定構造体
+type Config struct {
+	// 送り先（none・stdout・otlp）
+	Exporter string `yaml:"exporter"`
+	// OTLPの送り先（例: otel-collector:4318、空の場合はOTEL_EXPORTER_OTLP_ENDPOINTまたはlocalhost:4318）
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+	// OTLPをTLSなしで送るか（同じネットワーク内のコレクター用）
+	OTLPInsecure bool `yaml:"otlp_insecure"`
+	// 記録するトレースの割合（0〜1、上流から伝播した判定がある場合はそれに従う）
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// Validate はエクスポーターとサンプリングの割合を検証する
+func (c *Config) Validate() error {
+	var errs []error
+	switch c.Exporter {
+	case ExporterNone, ExporterStdout, ExporterOTLP:
+	default:
+		errs = append(errs, fmt.Errorf("unknown TRACING_EXPORTER: %s", c.Exporter))
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1: %v", c.SampleRatio))
+	}
+	return errors.Join(errs...)
+}
+
+// Setup は設定に応じたトレーサープロバイダーをグローバルに登録し、停止時に呼ぶ関数を返す
+// 停止時の関数は送信待ちのスパンを送り出してからエクスポーターを閉じる
+func Setup(ctx context.Context, config *Config, info buildinfo.Info) (func(context.Context) error, error) {
+	// traceparentヘッダーは無効の場合も引き継ぐ
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch config.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		stdout, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		exporter = stdout
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if config.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(config.OTLPEndpoint))
+		}
+		if config.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		otlp, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		exporter = otlp
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: %s", config.Exporter)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			semconv.ServiceName(ServiceName),
+			semconv.ServiceVersion(info.Version),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
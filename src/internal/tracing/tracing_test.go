@@ -0,0 +1,121 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"seicheese/internal/buildinfo"
+	"seicheese/internal/logging"
+	"seicheese/internal/tracing"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		config  tracing.Config
+		wantErr string
+	}{
+		{tracing.Config{Exporter: tracing.ExporterNone, SampleRatio: 1}, ""},
+		{tracing.Config{Exporter: tracing.ExporterOTLP, SampleRatio: 0.1}, ""},
+		{tracing.Config{Exporter: "jaeger", SampleRatio: 1}, "unknown TRACING_EXPORTER"},
+		{tracing.Config{Exporter: tracing.ExporterStdout, SampleRatio: 1.5}, "TRACING_SAMPLE_RATIO"},
+	}
+	for _, tt := range tests {
+		err := tt.config.Validate()
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("Validate(%+v) = %v, want %q", tt.config, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSetupNone(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), &tracing.Config{Exporter: tracing.ExporterNone}, buildinfo.Info{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	// traceparentヘッダーを解釈するプロパゲーターを登録する
+	if _, err := tracing.Setup(context.Background(), &tracing.Config{Exporter: tracing.ExporterNone}, buildinfo.Info{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var logs bytes.Buffer
+	e := echo.New()
+	e.Use(logging.Middleware(logging.New(&logs, slog.LevelInfo)))
+	e.Use(tracing.Middleware())
+
+	var handlerSpan trace.SpanContext
+	e.GET("/api/users/:id", func(c echo.Context) error {
+		handlerSpan = trace.SpanContextFromContext(c.Request().Context())
+		if c.Param("id") == "0" {
+			return errors.New("database is down")
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	// 上流から引き継いだトレースの子になる
+	const traceID = "4bf92f3577b34da6a3ce929d0e0736aa"
+	req := httptest.NewRequest(http.MethodGet, "/api/users/4", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	req.Header.Set(logging.HeaderRequestID, "req-1")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET /api/users/:id" || span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("span = %q (%v), want server span named after the route", span.Name(), span.SpanKind())
+	}
+	if span.SpanContext().TraceID().String() != traceID || span.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("span was not continued from traceparent: trace %s parent %s", span.SpanContext().TraceID(), span.Parent().SpanID())
+	}
+	if handlerSpan.SpanID() != span.SpanContext().SpanID() {
+		t.Error("request context does not carry the server span")
+	}
+
+	// アクセスログにサーバースパンのtrace_id・span_idが付く（ログとトレースを対応付けられる）
+	var access map[string]interface{}
+	if err := json.Unmarshal(logs.Bytes(), &access); err != nil {
+		t.Fatalf("access log %q: %v", logs.String(), err)
+	}
+	if access["msg"] != "access" || access["trace_id"] != traceID || access["span_id"] != span.SpanContext().SpanID().String() || access["request_id"] != "req-1" {
+		t.Errorf("access log = %v, want trace_id, span_id and request_id of the request", access)
+	}
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	if attrs["http.response.status_code"].AsInt64() != http.StatusOK || attrs["request_id"].AsString() != "req-1" || attrs["url.path"].AsString() != "/api/users/4" {
+		t.Errorf("attributes = %v", attrs)
+	}
+
+	// ハンドラーのエラーはHTTPErrorHandlerで確定したステータスとして記録する
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users/0", nil))
+	spans = recorder.Ended()
+	if got := spans[len(spans)-1].Status(); got.Code != codes.Error {
+		t.Errorf("status for 500 = %v, want Error", got)
+	}
+}
@@ -0,0 +1,99 @@
+// Seicheese-Backend/src/internal/utils/ngword.go
+
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// デフォルトのNGワード（設定のNG_WORDS_FILEで追加できる）
+var defaultNGWords = []string{
+	"死ね",
+	"殺す",
+	"ころす",
+	"きもい",
+	"うざい",
+	"fuck",
+	"shit",
+	"bitch",
+}
+
+// NGWords は文字列にNGワードが含まれているかを判定する
+// 起動時にLoadNGWordsで作成し、使う側に渡す
+type NGWords struct {
+	words []string
+}
+
+// defaultOnly はデフォルトのNGワードのみのNGWords（nilのNGWordsで使う）
+var defaultOnly = NewNGWords(nil)
+
+// NewNGWords はデフォルトのNGワードにwordsを加えたNGWordsを作成
+func NewNGWords(words []string) *NGWords {
+	n := &NGWords{}
+	for _, w := range append(append([]string{}, defaultNGWords...), words...) {
+		if normalized := normalizeForNGWord(w); normalized != "" {
+			n.words = append(n.words, normalized)
+		}
+	}
+	return n
+}
+
+// LoadNGWords はデフォルトのNGワードにpathのファイル（1行1語、#で始まる行は無視）の語を加えたNGWordsを作成
+// pathが空の場合はデフォルトのNGワードのみ
+func LoadNGWords(path string) (*NGWords, error) {
+	if path == "" {
+		return NewNGWords(nil), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open NG words file: %w", err)
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read NG words file: %w", err)
+	}
+	return NewNGWords(words), nil
+}
+
+// Contains 文字列にNGワードが含まれているかを判定（nilの場合はデフォルトのNGワードのみで判定）
+func (n *NGWords) Contains(text string) bool {
+	if n == nil {
+		n = defaultOnly
+	}
+
+	normalized := normalizeForNGWord(text)
+	for _, w := range n.words {
+		if strings.Contains(normalized, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// 全角・半角と大文字・小文字の揺れを吸収し、空白を除去
+func normalizeForNGWord(text string) string {
+	folded := strings.ToLower(width.Fold.String(text))
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, folded)
+}
@@ -0,0 +1,48 @@
+package utils_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"seicheese/internal/utils"
+)
+
+func TestLoadNGWords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ngwords.txt")
+	if err := os.WriteFile(path, []byte("# コメント\n\nばか\n  Ｓｐａｍ  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ngWords, err := utils.LoadNGWords(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"聖地巡礼が趣味です", false},
+		{"ばかです", true},
+		// 全角・半角、大文字・小文字、空白の揺れを吸収する
+		{"s p a m", true},
+		{"ＦＵＣＫ", true},
+		// コメント行はNGワードにしない
+		{"コメント", false},
+	}
+	for _, tt := range tests {
+		if got := ngWords.Contains(tt.text); got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+
+	// nilの場合はデフォルトのNGワードのみで判定する
+	var defaults *utils.NGWords
+	if !defaults.Contains("fuck") || defaults.Contains("ばか") {
+		t.Error("nil NGWords should use only the default words")
+	}
+
+	if _, err := utils.LoadNGWords(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("LoadNGWords(missing file): expected error")
+	}
+}
@@ -0,0 +1,72 @@
+// Seicheese-Backend/src/internal/validation/errors.go
+
+package validation
+
+import (
+	"reflect"
+	"strconv"
+
+	"seicheese/internal/apperror"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// toFieldError は検証エラーをクライアントに返すフィールドごとのエラーに変換する
+func toFieldError(fe validator.FieldError) apperror.FieldError {
+	field := apperror.FieldError{
+		Field: fe.Field(),
+		Code:  "FIELD_INVALID",
+	}
+
+	switch fe.Tag() {
+	case "required":
+		field.Code = "FIELD_REQUIRED"
+	case "maxrunes":
+		field.Code = "FIELD_TOO_LONG"
+		field.Params = map[string]interface{}{"max": param(fe.Param())}
+	case "minrunes":
+		field.Code = "FIELD_TOO_SHORT"
+		field.Params = map[string]interface{}{"min": param(fe.Param())}
+	case "max", "lte":
+		field.Code, field.Params = limitCode(fe, "max")
+	case "min", "gte":
+		field.Code, field.Params = limitCode(fe, "min")
+	case "latitude", "longitude":
+		field.Code = "FIELD_INVALID_COORDINATE"
+	case "japan_lat", "japan_lng":
+		field.Code = "FIELD_OUT_OF_JAPAN"
+	case "exists":
+		field.Code = "FIELD_NOT_FOUND"
+	}
+	return field
+}
+
+// limitCode はmin/maxタグのエラーコードを型に応じて決める
+func limitCode(fe validator.FieldError, name string) (string, map[string]interface{}) {
+	params := map[string]interface{}{name: param(fe.Param())}
+	switch fe.Kind() {
+	case reflect.String:
+		if name == "max" {
+			return "FIELD_TOO_LONG", params
+		}
+		return "FIELD_TOO_SHORT", params
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if name == "max" {
+			return "FIELD_TOO_MANY", params
+		}
+		return "FIELD_TOO_FEW", params
+	default:
+		if name == "max" {
+			return "FIELD_TOO_LARGE", params
+		}
+		return "FIELD_TOO_SMALL", params
+	}
+}
+
+// 数値のパラメータはJSONで数値として返す
+func param(p string) interface{} {
+	if n, err := strconv.Atoi(p); err == nil {
+		return n
+	}
+	return p
+}
@@ -0,0 +1,83 @@
+// Seicheese-Backend/src/internal/validation/rules.go
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// 日本周辺の緯度経度の範囲
+const (
+	JapanMinLatitude  = 24.396308
+	JapanMaxLatitude  = 45.551483
+	JapanMinLongitude = 122.93457
+	JapanMaxLongitude = 153.986672
+)
+
+func validateJapanLatitude(fl validator.FieldLevel) bool {
+	lat, ok := floatValue(fl.Field())
+	return ok && lat >= JapanMinLatitude && lat <= JapanMaxLatitude
+}
+
+func validateJapanLongitude(fl validator.FieldLevel) bool {
+	lng, ok := floatValue(fl.Field())
+	return ok && lng >= JapanMinLongitude && lng <= JapanMaxLongitude
+}
+
+func floatValue(field reflect.Value) (float64, bool) {
+	switch field.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func validateMinRunes(fl validator.FieldLevel) bool {
+	limit, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		panic(fmt.Sprintf("invalid minrunes param %q", fl.Param()))
+	}
+	return fl.Field().Kind() == reflect.String && utf8.RuneCountInString(fl.Field().String()) >= limit
+}
+
+func validateMaxRunes(fl validator.FieldLevel) bool {
+	limit, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		panic(fmt.Sprintf("invalid maxrunes param %q", fl.Param()))
+	}
+	return fl.Field().Kind() == reflect.String && utf8.RuneCountInString(fl.Field().String()) <= limit
+}
+
+func (v *Validator) validateExists(ctx context.Context, fl validator.FieldLevel) bool {
+	checker, ok := v.exists[fl.Param()]
+	if !ok {
+		panic(fmt.Sprintf("unknown exists param %q", fl.Param()))
+	}
+
+	var id int
+	switch fl.Field().Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		id = int(fl.Field().Int())
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		id = int(fl.Field().Uint())
+	default:
+		return false
+	}
+
+	exists, err := checker(ctx, id)
+	if err != nil {
+		// 検証関数はエラーを返せないため、検証後に内部エラーとして返す
+		if state, ok := ctx.Value(stateKey{}).(*validationState); ok && state.err == nil {
+			state.err = fmt.Errorf("failed to check existence of %s %d: %w", fl.Param(), id, err)
+		}
+		return false
+	}
+	return exists
+}
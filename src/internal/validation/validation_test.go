@@ -0,0 +1,162 @@
+package validation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"seicheese/internal/apperror"
+	"seicheese/internal/validation"
+)
+
+// fieldCodes は検証エラーのフィールドごとのコードを返す（検証エラーでない場合は失敗にする）
+func fieldCodes(t *testing.T, err error) map[string]string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	appErr, ok := apperror.As(err)
+	if !ok || !errors.Is(err, apperror.ErrValidationFailed) {
+		t.Fatalf("error = %v, want ErrValidationFailed", err)
+	}
+	fields, ok := appErr.Details.(apperror.FieldErrors)
+	if !ok {
+		t.Fatalf("details = %#v, want FieldErrors", appErr.Details)
+	}
+	codes := map[string]string{}
+	for _, field := range fields {
+		codes[field.Field] = field.Code
+	}
+	return codes
+}
+
+func TestJapanCoordinates(t *testing.T) {
+	type request struct {
+		Latitude  float64 `json:"latitude" validate:"japan_lat"`
+		Longitude float64 `json:"longitude" validate:"japan_lng"`
+	}
+	v := validation.New(nil)
+
+	tests := []struct {
+		name string
+		req  request
+		want map[string]string
+	}{
+		{"tokyo", request{35.6851, 139.7224}, nil},
+		{"min bounds", request{validation.JapanMinLatitude, validation.JapanMinLongitude}, nil},
+		{"max bounds", request{validation.JapanMaxLatitude, validation.JapanMaxLongitude}, nil},
+		{"below min", request{validation.JapanMinLatitude - 0.001, validation.JapanMinLongitude - 0.001},
+			map[string]string{"latitude": "FIELD_OUT_OF_JAPAN", "longitude": "FIELD_OUT_OF_JAPAN"}},
+		{"above max", request{validation.JapanMaxLatitude + 0.001, validation.JapanMaxLongitude + 0.001},
+			map[string]string{"latitude": "FIELD_OUT_OF_JAPAN", "longitude": "FIELD_OUT_OF_JAPAN"}},
+		// 緯度と経度の取り違え
+		{"swapped", request{139.7224, 35.6851}, map[string]string{"latitude": "FIELD_OUT_OF_JAPAN", "longitude": "FIELD_OUT_OF_JAPAN"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fieldCodes(t, v.Validate(&tt.req))
+			if len(got) != len(tt.want) {
+				t.Fatalf("field errors = %v, want %v", got, tt.want)
+			}
+			for field, code := range tt.want {
+				if got[field] != code {
+					t.Errorf("%s = %q, want %q", field, got[field], code)
+				}
+			}
+		})
+	}
+}
+
+func TestRunes(t *testing.T) {
+	type request struct {
+		Name string `json:"name" validate:"minrunes=2,maxrunes=5"`
+	}
+	v := validation.New(nil)
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		// バイト数ではなく文字数で数える（「聖地巡礼」は12バイト）
+		{"multibyte within limit", "聖地巡礼", ""},
+		{"multibyte at max", "聖地巡礼者", ""},
+		{"multibyte over max", "聖地巡礼者達", "FIELD_TOO_LONG"},
+		{"multibyte at min", "聖地", ""},
+		{"multibyte under min", "聖", "FIELD_TOO_SHORT"},
+		// 絵文字（4バイト）も1文字
+		{"emoji", "🗾🗻", ""},
+		{"emoji under min", "🗾", "FIELD_TOO_SHORT"},
+		{"ascii over max", "abcdef", "FIELD_TOO_LONG"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codes := fieldCodes(t, v.Validate(&request{Name: tt.value}))
+			if codes["name"] != tt.want {
+				t.Errorf("Validate(%q) = %v, want %q", tt.value, codes, tt.want)
+			}
+		})
+	}
+
+	// 上限・下限はパラメータとして返す
+	err := v.Validate(&request{Name: "聖地巡礼者達"})
+	appErr, _ := apperror.As(err)
+	fields, _ := appErr.Details.(apperror.FieldErrors)
+	if len(fields) != 1 || fields[0].Params["max"] != 5 {
+		t.Errorf("details = %#v, want max 5", appErr.Details)
+	}
+}
+
+func TestExists(t *testing.T) {
+	type request struct {
+		GenreID int `json:"genre_id" validate:"required,exists=genre"`
+	}
+	dbErr := errors.New("connection refused")
+
+	tests := []struct {
+		name    string
+		checker func(ctx context.Context, id int) (bool, error)
+		// wantErr は期待するエラー（nilの場合は検証に通る）
+		wantErr *apperror.Error
+		// wantCode は検証エラーの場合のフィールドのコード
+		wantCode string
+	}{
+		{"exists", func(ctx context.Context, id int) (bool, error) { return true, nil }, nil, ""},
+		{"not found", func(ctx context.Context, id int) (bool, error) { return false, nil }, apperror.ErrValidationFailed, "FIELD_NOT_FOUND"},
+		// DBのエラーは入力の誤りではなく内部エラーとして返す
+		{"db error", func(ctx context.Context, id int) (bool, error) { return false, dbErr }, apperror.ErrInternal, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checkedID int
+			v := validation.New(validation.ExistsCheckers{
+				"genre": func(ctx context.Context, id int) (bool, error) {
+					checkedID = id
+					return tt.checker(ctx, id)
+				},
+			})
+
+			err := v.ValidateContext(context.Background(), &request{GenreID: 3})
+			if checkedID != 3 {
+				t.Errorf("checked id %d, want 3", checkedID)
+			}
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("ValidateContext = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateContext = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == apperror.ErrInternal && !errors.Is(err, dbErr) {
+				t.Errorf("ValidateContext = %v, want it to wrap the DB error", err)
+			}
+			if tt.wantCode != "" {
+				if codes := fieldCodes(t, err); codes["genre_id"] != tt.wantCode {
+					t.Errorf("field errors = %v, want genre_id %q", codes, tt.wantCode)
+				}
+			}
+		})
+	}
+}
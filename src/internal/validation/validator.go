@@ -0,0 +1,100 @@
+// Seicheese-Backend/src/internal/validation/validator.go
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"seicheese/internal/apperror"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Validator はリクエスト構造体のvalidateタグを検証する
+// e.Validator に設定して使う
+//
+// 標準のタグに加えて以下のタグが使える
+//   - japan_lat, japan_lng: 日本周辺の緯度・経度の範囲
+//   - minrunes, maxrunes: 文字数（バイト数ではなくrune数）
+//   - exists=<名前>: 外部キーの参照先が存在すること（Newに渡したExistsCheckersの名前）
+type Validator struct {
+	validate *validator.Validate
+	exists   ExistsCheckers
+}
+
+// ExistsCheckers はexistsタグの名前ごとの存在確認の関数
+type ExistsCheckers map[string]func(ctx context.Context, id int) (bool, error)
+
+// New はValidatorを作成
+func New(exists ExistsCheckers) *Validator {
+	v := &Validator{
+		validate: validator.New(validator.WithRequiredStructEnabled()),
+		exists:   exists,
+	}
+
+	// エラーのフィールド名はJSONの名前にする
+	v.validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	mustRegister(v.validate.RegisterValidation("japan_lat", validateJapanLatitude))
+	mustRegister(v.validate.RegisterValidation("japan_lng", validateJapanLongitude))
+	mustRegister(v.validate.RegisterValidation("minrunes", validateMinRunes))
+	mustRegister(v.validate.RegisterValidation("maxrunes", validateMaxRunes))
+	mustRegister(v.validate.RegisterValidationCtx("exists", v.validateExists))
+
+	return v
+}
+
+// validationState は1回の検証中に起きたエラー（DBエラーなど）を保持する
+type validationState struct {
+	err error
+}
+
+type stateKey struct{}
+
+func mustRegister(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Validate はechoのValidatorインターフェースの実装
+func (v *Validator) Validate(i interface{}) error {
+	return v.ValidateContext(context.Background(), i)
+}
+
+// ValidateContext はリクエストのContextを使って検証する（existsタグのDB問い合わせに使う）
+// 検証エラーはフィールドごとの詳細を付けた apperror.ErrValidationFailed で返す
+func (v *Validator) ValidateContext(ctx context.Context, i interface{}) error {
+	state := &validationState{}
+	err := v.validate.StructCtx(context.WithValue(ctx, stateKey{}, state), i)
+	if state.err != nil {
+		return apperror.ErrInternal.Wrap(state.err)
+	}
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return fmt.Errorf("failed to validate request: %w", err)
+	}
+
+	fields := make(apperror.FieldErrors, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, toFieldError(fe))
+	}
+	return apperror.ErrValidationFailed.WithDetails(fields)
+}
@@ -24,42 +24,47 @@ import (
 
 // Content is an object representing the database table.
 type Content struct {
-	ContentID   int       `boil:"content_id" json:"content_id" toml:"content_id" yaml:"content_id"`
-	ContentName string    `boil:"content_name" json:"content_name" toml:"content_name" yaml:"content_name"`
-	GenreID     int       `boil:"genre_id" json:"genre_id" toml:"genre_id" yaml:"genre_id"`
-	CreatedAt   null.Time `boil:"created_at" json:"created_at,omitempty" toml:"created_at" yaml:"created_at,omitempty"`
-	UpdatedAt   null.Time `boil:"updated_at" json:"updated_at,omitempty" toml:"updated_at" yaml:"updated_at,omitempty"`
+	ContentID     int         `boil:"content_id" json:"content_id" toml:"content_id" yaml:"content_id"`
+	ContentName   string      `boil:"content_name" json:"content_name" toml:"content_name" yaml:"content_name"`
+	ContentNameEn null.String `boil:"content_name_en" json:"content_name_en,omitempty" toml:"content_name_en" yaml:"content_name_en,omitempty"`
+	GenreID       int         `boil:"genre_id" json:"genre_id" toml:"genre_id" yaml:"genre_id"`
+	CreatedAt     null.Time   `boil:"created_at" json:"created_at,omitempty" toml:"created_at" yaml:"created_at,omitempty"`
+	UpdatedAt     null.Time   `boil:"updated_at" json:"updated_at,omitempty" toml:"updated_at" yaml:"updated_at,omitempty"`
 
 	R *contentR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L contentL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var ContentColumns = struct {
-	ContentID   string
-	ContentName string
-	GenreID     string
-	CreatedAt   string
-	UpdatedAt   string
+	ContentID     string
+	ContentName   string
+	ContentNameEn string
+	GenreID       string
+	CreatedAt     string
+	UpdatedAt     string
 }{
-	ContentID:   "content_id",
-	ContentName: "content_name",
-	GenreID:     "genre_id",
-	CreatedAt:   "created_at",
-	UpdatedAt:   "updated_at",
+	ContentID:     "content_id",
+	ContentName:   "content_name",
+	ContentNameEn: "content_name_en",
+	GenreID:       "genre_id",
+	CreatedAt:     "created_at",
+	UpdatedAt:     "updated_at",
 }
 
 var ContentTableColumns = struct {
-	ContentID   string
-	ContentName string
-	GenreID     string
-	CreatedAt   string
-	UpdatedAt   string
+	ContentID     string
+	ContentName   string
+	ContentNameEn string
+	GenreID       string
+	CreatedAt     string
+	UpdatedAt     string
 }{
-	ContentID:   "contents.content_id",
-	ContentName: "contents.content_name",
-	GenreID:     "contents.genre_id",
-	CreatedAt:   "contents.created_at",
-	UpdatedAt:   "contents.updated_at",
+	ContentID:     "contents.content_id",
+	ContentName:   "contents.content_name",
+	ContentNameEn: "contents.content_name_en",
+	GenreID:       "contents.genre_id",
+	CreatedAt:     "contents.created_at",
+	UpdatedAt:     "contents.updated_at",
 }
 
 // Generated where
@@ -89,6 +94,50 @@ func (w whereHelperstring) NIN(slice []string) qm.QueryMod {
 	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
 }
 
+type whereHelpernull_String struct{ field string }
+
+func (w whereHelpernull_String) EQ(x null.String) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_String) NEQ(x null.String) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_String) LT(x null.String) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_String) LTE(x null.String) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_String) GT(x null.String) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_String) GTE(x null.String) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+func (w whereHelpernull_String) LIKE(x null.String) qm.QueryMod {
+	return qm.Where(w.field+" LIKE ?", x)
+}
+func (w whereHelpernull_String) NLIKE(x null.String) qm.QueryMod {
+	return qm.Where(w.field+" NOT LIKE ?", x)
+}
+func (w whereHelpernull_String) IN(slice []string) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelpernull_String) NIN(slice []string) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
+func (w whereHelpernull_String) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_String) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+
 type whereHelpernull_Time struct{ field string }
 
 func (w whereHelpernull_Time) EQ(x null.Time) qm.QueryMod {
@@ -114,17 +163,19 @@ func (w whereHelpernull_Time) IsNull() qm.QueryMod    { return qmhelper.WhereIsN
 func (w whereHelpernull_Time) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
 
 var ContentWhere = struct {
-	ContentID   whereHelperint
-	ContentName whereHelperstring
-	GenreID     whereHelperint
-	CreatedAt   whereHelpernull_Time
-	UpdatedAt   whereHelpernull_Time
+	ContentID     whereHelperint
+	ContentName   whereHelperstring
+	ContentNameEn whereHelpernull_String
+	GenreID       whereHelperint
+	CreatedAt     whereHelpernull_Time
+	UpdatedAt     whereHelpernull_Time
 }{
-	ContentID:   whereHelperint{field: "`contents`.`content_id`"},
-	ContentName: whereHelperstring{field: "`contents`.`content_name`"},
-	GenreID:     whereHelperint{field: "`contents`.`genre_id`"},
-	CreatedAt:   whereHelpernull_Time{field: "`contents`.`created_at`"},
-	UpdatedAt:   whereHelpernull_Time{field: "`contents`.`updated_at`"},
+	ContentID:     whereHelperint{field: "`contents`.`content_id`"},
+	ContentName:   whereHelperstring{field: "`contents`.`content_name`"},
+	ContentNameEn: whereHelpernull_String{field: "`contents`.`content_name_en`"},
+	GenreID:       whereHelperint{field: "`contents`.`genre_id`"},
+	CreatedAt:     whereHelpernull_Time{field: "`contents`.`created_at`"},
+	UpdatedAt:     whereHelpernull_Time{field: "`contents`.`updated_at`"},
 }
 
 // ContentRels is where relationship names are stored.
@@ -165,8 +216,8 @@ func (r *contentR) GetSeichies() SeichySlice {
 type contentL struct{}
 
 var (
-	contentAllColumns            = []string{"content_id", "content_name", "genre_id", "created_at", "updated_at"}
-	contentColumnsWithoutDefault = []string{"content_name", "genre_id"}
+	contentAllColumns            = []string{"content_id", "content_name", "content_name_en", "genre_id", "created_at", "updated_at"}
+	contentColumnsWithoutDefault = []string{"content_name", "content_name_en", "genre_id"}
 	contentColumnsWithDefault    = []string{"content_id", "created_at", "updated_at"}
 	contentPrimaryKeyColumns     = []string{"content_id"}
 	contentGeneratedColumns      = []string{}
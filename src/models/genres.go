@@ -24,51 +24,58 @@ import (
 
 // Genre is an object representing the database table.
 type Genre struct {
-	GenreID   int       `boil:"genre_id" json:"genre_id" toml:"genre_id" yaml:"genre_id"`
-	GenreName string    `boil:"genre_name" json:"genre_name" toml:"genre_name" yaml:"genre_name"`
-	CreatedAt null.Time `boil:"created_at" json:"created_at,omitempty" toml:"created_at" yaml:"created_at,omitempty"`
-	UpdatedAt null.Time `boil:"updated_at" json:"updated_at,omitempty" toml:"updated_at" yaml:"updated_at,omitempty"`
+	GenreID     int         `boil:"genre_id" json:"genre_id" toml:"genre_id" yaml:"genre_id"`
+	GenreName   string      `boil:"genre_name" json:"genre_name" toml:"genre_name" yaml:"genre_name"`
+	GenreNameEn null.String `boil:"genre_name_en" json:"genre_name_en,omitempty" toml:"genre_name_en" yaml:"genre_name_en,omitempty"`
+	CreatedAt   null.Time   `boil:"created_at" json:"created_at,omitempty" toml:"created_at" yaml:"created_at,omitempty"`
+	UpdatedAt   null.Time   `boil:"updated_at" json:"updated_at,omitempty" toml:"updated_at" yaml:"updated_at,omitempty"`
 
 	R *genreR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L genreL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var GenreColumns = struct {
-	GenreID   string
-	GenreName string
-	CreatedAt string
-	UpdatedAt string
+	GenreID     string
+	GenreName   string
+	GenreNameEn string
+	CreatedAt   string
+	UpdatedAt   string
 }{
-	GenreID:   "genre_id",
-	GenreName: "genre_name",
-	CreatedAt: "created_at",
-	UpdatedAt: "updated_at",
+	GenreID:     "genre_id",
+	GenreName:   "genre_name",
+	GenreNameEn: "genre_name_en",
+	CreatedAt:   "created_at",
+	UpdatedAt:   "updated_at",
 }
 
 var GenreTableColumns = struct {
-	GenreID   string
-	GenreName string
-	CreatedAt string
-	UpdatedAt string
+	GenreID     string
+	GenreName   string
+	GenreNameEn string
+	CreatedAt   string
+	UpdatedAt   string
 }{
-	GenreID:   "genres.genre_id",
-	GenreName: "genres.genre_name",
-	CreatedAt: "genres.created_at",
-	UpdatedAt: "genres.updated_at",
+	GenreID:     "genres.genre_id",
+	GenreName:   "genres.genre_name",
+	GenreNameEn: "genres.genre_name_en",
+	CreatedAt:   "genres.created_at",
+	UpdatedAt:   "genres.updated_at",
 }
 
 // Generated where
 
 var GenreWhere = struct {
-	GenreID   whereHelperint
-	GenreName whereHelperstring
-	CreatedAt whereHelpernull_Time
-	UpdatedAt whereHelpernull_Time
+	GenreID     whereHelperint
+	GenreName   whereHelperstring
+	GenreNameEn whereHelpernull_String
+	CreatedAt   whereHelpernull_Time
+	UpdatedAt   whereHelpernull_Time
 }{
-	GenreID:   whereHelperint{field: "`genres`.`genre_id`"},
-	GenreName: whereHelperstring{field: "`genres`.`genre_name`"},
-	CreatedAt: whereHelpernull_Time{field: "`genres`.`created_at`"},
-	UpdatedAt: whereHelpernull_Time{field: "`genres`.`updated_at`"},
+	GenreID:     whereHelperint{field: "`genres`.`genre_id`"},
+	GenreName:   whereHelperstring{field: "`genres`.`genre_name`"},
+	GenreNameEn: whereHelpernull_String{field: "`genres`.`genre_name_en`"},
+	CreatedAt:   whereHelpernull_Time{field: "`genres`.`created_at`"},
+	UpdatedAt:   whereHelpernull_Time{field: "`genres`.`updated_at`"},
 }
 
 // GenreRels is where relationship names are stored.
@@ -99,8 +106,8 @@ func (r *genreR) GetContents() ContentSlice {
 type genreL struct{}
 
 var (
-	genreAllColumns            = []string{"genre_id", "genre_name", "created_at", "updated_at"}
-	genreColumnsWithoutDefault = []string{"genre_name"}
+	genreAllColumns            = []string{"genre_id", "genre_name", "genre_name_en", "created_at", "updated_at"}
+	genreColumnsWithoutDefault = []string{"genre_name", "genre_name_en"}
 	genreColumnsWithDefault    = []string{"genre_id", "created_at", "updated_at"}
 	genrePrimaryKeyColumns     = []string{"genre_id"}
 	genreGeneratedColumns      = []string{}
@@ -26,7 +26,7 @@ import (
 // Seichy is an object representing the database table.
 type Seichy struct {
 	SeichiID   int           `boil:"seichi_id" json:"seichi_id" toml:"seichi_id" yaml:"seichi_id"`
-	UserID     uint          `boil:"user_id" json:"user_id" toml:"user_id" yaml:"user_id"`
+	UserID     null.Uint     `boil:"user_id" json:"user_id,omitempty" toml:"user_id" yaml:"user_id,omitempty"`
 	SeichiName string        `boil:"seichi_name" json:"seichi_name" toml:"seichi_name" yaml:"seichi_name"`
 	Comment    null.String   `boil:"comment" json:"comment,omitempty" toml:"comment" yaml:"comment,omitempty"`
 	Latitude   types.Decimal `boil:"latitude" json:"latitude" toml:"latitude" yaml:"latitude"`
@@ -90,40 +90,34 @@ var SeichyTableColumns = struct {
 
 // Generated where
 
-type whereHelpernull_String struct{ field string }
+type whereHelpernull_Uint struct{ field string }
 
-func (w whereHelpernull_String) EQ(x null.String) qm.QueryMod {
+func (w whereHelpernull_Uint) EQ(x null.Uint) qm.QueryMod {
 	return qmhelper.WhereNullEQ(w.field, false, x)
 }
-func (w whereHelpernull_String) NEQ(x null.String) qm.QueryMod {
+func (w whereHelpernull_Uint) NEQ(x null.Uint) qm.QueryMod {
 	return qmhelper.WhereNullEQ(w.field, true, x)
 }
-func (w whereHelpernull_String) LT(x null.String) qm.QueryMod {
+func (w whereHelpernull_Uint) LT(x null.Uint) qm.QueryMod {
 	return qmhelper.Where(w.field, qmhelper.LT, x)
 }
-func (w whereHelpernull_String) LTE(x null.String) qm.QueryMod {
+func (w whereHelpernull_Uint) LTE(x null.Uint) qm.QueryMod {
 	return qmhelper.Where(w.field, qmhelper.LTE, x)
 }
-func (w whereHelpernull_String) GT(x null.String) qm.QueryMod {
+func (w whereHelpernull_Uint) GT(x null.Uint) qm.QueryMod {
 	return qmhelper.Where(w.field, qmhelper.GT, x)
 }
-func (w whereHelpernull_String) GTE(x null.String) qm.QueryMod {
+func (w whereHelpernull_Uint) GTE(x null.Uint) qm.QueryMod {
 	return qmhelper.Where(w.field, qmhelper.GTE, x)
 }
-func (w whereHelpernull_String) LIKE(x null.String) qm.QueryMod {
-	return qm.Where(w.field+" LIKE ?", x)
-}
-func (w whereHelpernull_String) NLIKE(x null.String) qm.QueryMod {
-	return qm.Where(w.field+" NOT LIKE ?", x)
-}
-func (w whereHelpernull_String) IN(slice []string) qm.QueryMod {
+func (w whereHelpernull_Uint) IN(slice []uint) qm.QueryMod {
 	values := make([]interface{}, 0, len(slice))
 	for _, value := range slice {
 		values = append(values, value)
 	}
 	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
 }
-func (w whereHelpernull_String) NIN(slice []string) qm.QueryMod {
+func (w whereHelpernull_Uint) NIN(slice []uint) qm.QueryMod {
 	values := make([]interface{}, 0, len(slice))
 	for _, value := range slice {
 		values = append(values, value)
@@ -131,8 +125,8 @@ func (w whereHelpernull_String) NIN(slice []string) qm.QueryMod {
 	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
 }
 
-func (w whereHelpernull_String) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
-func (w whereHelpernull_String) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+func (w whereHelpernull_Uint) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_Uint) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
 
 type whereHelpertypes_Decimal struct{ field string }
 
@@ -157,7 +151,7 @@ func (w whereHelpertypes_Decimal) GTE(x types.Decimal) qm.QueryMod {
 
 var SeichyWhere = struct {
 	SeichiID   whereHelperint
-	UserID     whereHelperuint
+	UserID     whereHelpernull_Uint
 	SeichiName whereHelperstring
 	Comment    whereHelpernull_String
 	Latitude   whereHelpertypes_Decimal
@@ -168,7 +162,7 @@ var SeichyWhere = struct {
 	UpdatedAt  whereHelpernull_Time
 }{
 	SeichiID:   whereHelperint{field: "`seichies`.`seichi_id`"},
-	UserID:     whereHelperuint{field: "`seichies`.`user_id`"},
+	UserID:     whereHelpernull_Uint{field: "`seichies`.`user_id`"},
 	SeichiName: whereHelperstring{field: "`seichies`.`seichi_name`"},
 	Comment:    whereHelpernull_String{field: "`seichies`.`comment`"},
 	Latitude:   whereHelpertypes_Decimal{field: "`seichies`.`latitude`"},
@@ -605,7 +599,9 @@ func (seichyL) LoadUser(ctx context.Context, e boil.ContextExecutor, singular bo
 		if object.R == nil {
 			object.R = &seichyR{}
 		}
-		args[object.UserID] = struct{}{}
+		if !queries.IsNil(object.UserID) {
+			args[object.UserID] = struct{}{}
+		}
 
 	} else {
 		for _, obj := range slice {
@@ -613,7 +609,9 @@ func (seichyL) LoadUser(ctx context.Context, e boil.ContextExecutor, singular bo
 				obj.R = &seichyR{}
 			}
 
-			args[obj.UserID] = struct{}{}
+			if !queries.IsNil(obj.UserID) {
+				args[obj.UserID] = struct{}{}
+			}
 
 		}
 	}
@@ -678,7 +676,7 @@ func (seichyL) LoadUser(ctx context.Context, e boil.ContextExecutor, singular bo
 
 	for _, local := range slice {
 		for _, foreign := range resultSlice {
-			if local.UserID == foreign.UserID {
+			if queries.Equal(local.UserID, foreign.UserID) {
 				local.R.User = foreign
 				if foreign.R == nil {
 					foreign.R = &userR{}
@@ -959,7 +957,7 @@ func (o *Seichy) SetUser(ctx context.Context, exec boil.ContextExecutor, insert
 		return errors.Wrap(err, "failed to update local table")
 	}
 
-	o.UserID = related.UserID
+	queries.Assign(&o.UserID, related.UserID)
 	if o.R == nil {
 		o.R = &seichyR{
 			User: related,
@@ -979,6 +977,39 @@ func (o *Seichy) SetUser(ctx context.Context, exec boil.ContextExecutor, insert
 	return nil
 }
 
+// RemoveUser relationship.
+// Sets o.R.User to nil.
+// Removes o from all passed in related items' relationships struct.
+func (o *Seichy) RemoveUser(ctx context.Context, exec boil.ContextExecutor, related *User) error {
+	var err error
+
+	queries.SetScanner(&o.UserID, nil)
+	if _, err = o.Update(ctx, exec, boil.Whitelist("user_id")); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	if o.R != nil {
+		o.R.User = nil
+	}
+	if related == nil || related.R == nil {
+		return nil
+	}
+
+	for i, ri := range related.R.Seichies {
+		if queries.Equal(o.UserID, ri.UserID) {
+			continue
+		}
+
+		ln := len(related.R.Seichies)
+		if ln > 1 && i < ln-1 {
+			related.R.Seichies[i] = related.R.Seichies[ln-1]
+		}
+		related.R.Seichies = related.R.Seichies[:ln-1]
+		break
+	}
+	return nil
+}
+
 // SetPlace of the seichy to the related item.
 // Sets o.R.Place to related.
 // Adds o to related.R.Seichies.
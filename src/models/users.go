@@ -24,58 +24,110 @@ import (
 
 // User is an object representing the database table.
 type User struct {
-	UserID     uint      `boil:"user_id" json:"user_id" toml:"user_id" yaml:"user_id"`
-	FirebaseID string    `boil:"firebase_id" json:"firebase_id" toml:"firebase_id" yaml:"firebase_id"`
-	IsAdmin    bool      `boil:"is_admin" json:"is_admin" toml:"is_admin" yaml:"is_admin"`
-	CreatedAt  null.Time `boil:"created_at" json:"created_at,omitempty" toml:"created_at" yaml:"created_at,omitempty"`
-	UpdatedAt  null.Time `boil:"updated_at" json:"updated_at,omitempty" toml:"updated_at" yaml:"updated_at,omitempty"`
+	UserID           uint        `boil:"user_id" json:"user_id" toml:"user_id" yaml:"user_id"`
+	FirebaseID       string      `boil:"firebase_id" json:"firebase_id" toml:"firebase_id" yaml:"firebase_id"`
+	DisplayName      null.String `boil:"display_name" json:"display_name,omitempty" toml:"display_name" yaml:"display_name,omitempty"`
+	AvatarURL        null.String `boil:"avatar_url" json:"avatar_url,omitempty" toml:"avatar_url" yaml:"avatar_url,omitempty"`
+	Bio              null.String `boil:"bio" json:"bio,omitempty" toml:"bio" yaml:"bio,omitempty"`
+	FavoriteGenreIds null.JSON   `boil:"favorite_genre_ids" json:"favorite_genre_ids,omitempty" toml:"favorite_genre_ids" yaml:"favorite_genre_ids,omitempty"`
+	IsAdmin          bool        `boil:"is_admin" json:"is_admin" toml:"is_admin" yaml:"is_admin"`
+	CreatedAt        null.Time   `boil:"created_at" json:"created_at,omitempty" toml:"created_at" yaml:"created_at,omitempty"`
+	UpdatedAt        null.Time   `boil:"updated_at" json:"updated_at,omitempty" toml:"updated_at" yaml:"updated_at,omitempty"`
 
 	R *userR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L userL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var UserColumns = struct {
-	UserID     string
-	FirebaseID string
-	IsAdmin    string
-	CreatedAt  string
-	UpdatedAt  string
+	UserID           string
+	FirebaseID       string
+	DisplayName      string
+	AvatarURL        string
+	Bio              string
+	FavoriteGenreIds string
+	IsAdmin          string
+	CreatedAt        string
+	UpdatedAt        string
 }{
-	UserID:     "user_id",
-	FirebaseID: "firebase_id",
-	IsAdmin:    "is_admin",
-	CreatedAt:  "created_at",
-	UpdatedAt:  "updated_at",
+	UserID:           "user_id",
+	FirebaseID:       "firebase_id",
+	DisplayName:      "display_name",
+	AvatarURL:        "avatar_url",
+	Bio:              "bio",
+	FavoriteGenreIds: "favorite_genre_ids",
+	IsAdmin:          "is_admin",
+	CreatedAt:        "created_at",
+	UpdatedAt:        "updated_at",
 }
 
 var UserTableColumns = struct {
-	UserID     string
-	FirebaseID string
-	IsAdmin    string
-	CreatedAt  string
-	UpdatedAt  string
+	UserID           string
+	FirebaseID       string
+	DisplayName      string
+	AvatarURL        string
+	Bio              string
+	FavoriteGenreIds string
+	IsAdmin          string
+	CreatedAt        string
+	UpdatedAt        string
 }{
-	UserID:     "users.user_id",
-	FirebaseID: "users.firebase_id",
-	IsAdmin:    "users.is_admin",
-	CreatedAt:  "users.created_at",
-	UpdatedAt:  "users.updated_at",
+	UserID:           "users.user_id",
+	FirebaseID:       "users.firebase_id",
+	DisplayName:      "users.display_name",
+	AvatarURL:        "users.avatar_url",
+	Bio:              "users.bio",
+	FavoriteGenreIds: "users.favorite_genre_ids",
+	IsAdmin:          "users.is_admin",
+	CreatedAt:        "users.created_at",
+	UpdatedAt:        "users.updated_at",
 }
 
 // Generated where
 
+type whereHelpernull_JSON struct{ field string }
+
+func (w whereHelpernull_JSON) EQ(x null.JSON) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_JSON) NEQ(x null.JSON) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_JSON) LT(x null.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_JSON) LTE(x null.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_JSON) GT(x null.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_JSON) GTE(x null.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
+func (w whereHelpernull_JSON) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_JSON) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+
 var UserWhere = struct {
-	UserID     whereHelperuint
-	FirebaseID whereHelperstring
-	IsAdmin    whereHelperbool
-	CreatedAt  whereHelpernull_Time
-	UpdatedAt  whereHelpernull_Time
+	UserID           whereHelperuint
+	FirebaseID       whereHelperstring
+	DisplayName      whereHelpernull_String
+	AvatarURL        whereHelpernull_String
+	Bio              whereHelpernull_String
+	FavoriteGenreIds whereHelpernull_JSON
+	IsAdmin          whereHelperbool
+	CreatedAt        whereHelpernull_Time
+	UpdatedAt        whereHelpernull_Time
 }{
-	UserID:     whereHelperuint{field: "`users`.`user_id`"},
-	FirebaseID: whereHelperstring{field: "`users`.`firebase_id`"},
-	IsAdmin:    whereHelperbool{field: "`users`.`is_admin`"},
-	CreatedAt:  whereHelpernull_Time{field: "`users`.`created_at`"},
-	UpdatedAt:  whereHelpernull_Time{field: "`users`.`updated_at`"},
+	UserID:           whereHelperuint{field: "`users`.`user_id`"},
+	FirebaseID:       whereHelperstring{field: "`users`.`firebase_id`"},
+	DisplayName:      whereHelpernull_String{field: "`users`.`display_name`"},
+	AvatarURL:        whereHelpernull_String{field: "`users`.`avatar_url`"},
+	Bio:              whereHelpernull_String{field: "`users`.`bio`"},
+	FavoriteGenreIds: whereHelpernull_JSON{field: "`users`.`favorite_genre_ids`"},
+	IsAdmin:          whereHelperbool{field: "`users`.`is_admin`"},
+	CreatedAt:        whereHelpernull_Time{field: "`users`.`created_at`"},
+	UpdatedAt:        whereHelpernull_Time{field: "`users`.`updated_at`"},
 }
 
 // UserRels is where relationship names are stored.
@@ -136,8 +188,8 @@ func (r *userR) GetSeichies() SeichySlice {
 type userL struct{}
 
 var (
-	userAllColumns            = []string{"user_id", "firebase_id", "is_admin", "created_at", "updated_at"}
-	userColumnsWithoutDefault = []string{"firebase_id"}
+	userAllColumns            = []string{"user_id", "firebase_id", "display_name", "avatar_url", "bio", "favorite_genre_ids", "is_admin", "created_at", "updated_at"}
+	userColumnsWithoutDefault = []string{"firebase_id", "display_name", "avatar_url", "bio", "favorite_genre_ids"}
 	userColumnsWithDefault    = []string{"user_id", "is_admin", "created_at", "updated_at"}
 	userPrimaryKeyColumns     = []string{"user_id"}
 	userGeneratedColumns      = []string{}
@@ -943,7 +995,7 @@ func (userL) LoadSeichies(ctx context.Context, e boil.ContextExecutor, singular
 
 	for _, foreign := range resultSlice {
 		for _, local := range slice {
-			if local.UserID == foreign.UserID {
+			if queries.Equal(local.UserID, foreign.UserID) {
 				local.R.Seichies = append(local.R.Seichies, foreign)
 				if foreign.R == nil {
 					foreign.R = &seichyR{}
@@ -1121,7 +1173,7 @@ func (o *User) AddSeichies(ctx context.Context, exec boil.ContextExecutor, inser
 	var err error
 	for _, rel := range related {
 		if insert {
-			rel.UserID = o.UserID
+			queries.Assign(&rel.UserID, o.UserID)
 			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
 				return errors.Wrap(err, "failed to insert into foreign table")
 			}
@@ -1142,7 +1194,7 @@ func (o *User) AddSeichies(ctx context.Context, exec boil.ContextExecutor, inser
 				return errors.Wrap(err, "failed to update foreign table")
 			}
 
-			rel.UserID = o.UserID
+			queries.Assign(&rel.UserID, o.UserID)
 		}
 	}
 
@@ -1166,6 +1218,80 @@ func (o *User) AddSeichies(ctx context.Context, exec boil.ContextExecutor, inser
 	return nil
 }
 
+// SetSeichies removes all previously related items of the
+// user replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.User's Seichies accordingly.
+// Replaces o.R.Seichies with related.
+// Sets related.R.User's Seichies accordingly.
+func (o *User) SetSeichies(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*Seichy) error {
+	query := "update `seichies` set `user_id` = null where `user_id` = ?"
+	values := []interface{}{o.UserID}
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, query)
+		fmt.Fprintln(writer, values)
+	}
+	_, err := exec.ExecContext(ctx, query, values...)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove relationships before set")
+	}
+
+	if o.R != nil {
+		for _, rel := range o.R.Seichies {
+			queries.SetScanner(&rel.UserID, nil)
+			if rel.R == nil {
+				continue
+			}
+
+			rel.R.User = nil
+		}
+		o.R.Seichies = nil
+	}
+
+	return o.AddSeichies(ctx, exec, insert, related...)
+}
+
+// RemoveSeichies relationships from objects passed in.
+// Removes related items from R.Seichies (uses pointer comparison, removal does not keep order)
+// Sets related.R.User.
+func (o *User) RemoveSeichies(ctx context.Context, exec boil.ContextExecutor, related ...*Seichy) error {
+	if len(related) == 0 {
+		return nil
+	}
+
+	var err error
+	for _, rel := range related {
+		queries.SetScanner(&rel.UserID, nil)
+		if rel.R != nil {
+			rel.R.User = nil
+		}
+		if _, err = rel.Update(ctx, exec, boil.Whitelist("user_id")); err != nil {
+			return err
+		}
+	}
+	if o.R == nil {
+		return nil
+	}
+
+	for _, rel := range related {
+		for i, ri := range o.R.Seichies {
+			if rel != ri {
+				continue
+			}
+
+			ln := len(o.R.Seichies)
+			if ln > 1 && i < ln-1 {
+				o.R.Seichies[i] = o.R.Seichies[ln-1]
+			}
+			o.R.Seichies = o.R.Seichies[:ln-1]
+			break
+		}
+	}
+
+	return nil
+}
+
 // Users retrieves all the records using an executor.
 func Users(mods ...qm.QueryMod) userQuery {
 	mods = append(mods, qm.From("`users`"))
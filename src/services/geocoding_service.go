@@ -1,26 +1,68 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
+
+	"seicheese/internal/metrics"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-type GeocodingService struct{}
+const tracerName = "seicheese/services"
+
+// Geocoder は緯度経度から住所と郵便番号（"address"と"postalCode"）を取得する
+type Geocoder interface {
+	// GetAddressFromLatLng は住所の構成要素から組み立てた住所を返す
+	GetAddressFromLatLng(ctx context.Context, lat, lng float64) (map[string]string, error)
+	// GetFormattedAddress はGoogle Maps APIの整形済み住所を返す
+	GetFormattedAddress(ctx context.Context, lat, lng float64) (map[string]string, error)
+}
+
+// ErrNoAddress は指定した位置に住所がない場合（海上など、Geocoding APIがZERO_RESULTSを返した場合）のエラー
+var ErrNoAddress = errors.New("no address found for the location")
+
+// Geocoding APIのホスト（スパンに記録する）
+const geocodingHost = "maps.googleapis.com"
 
-func (s *GeocodingService) GetAddressFromLatLng(lat, lng float64) (map[string]string, error) {
-	apiKey := os.Getenv("GOOGLE_MAPS_API_KEY")
-	url := fmt.Sprintf("https://maps.googleapis.com/maps/api/geocode/json?latlng=%f,%f&key=%s&language=ja", lat, lng, apiKey)
+var _ Geocoder = (*GeocodingService)(nil)
+
+type GeocodingService struct {
+	// Google Maps Geocoding APIのキー
+	APIKey string
+}
 
-	resp, err := http.Get(url)
+// CheckConfig はAPIキーが設定されているか確認する（準備状態の確認用）
+func (s *GeocodingService) CheckConfig(ctx context.Context) error {
+	if s.APIKey == "" {
+		return errors.New("GOOGLE_MAPS_API_KEY is not set")
+	}
+	return nil
+}
+
+func (s *GeocodingService) GetAddressFromLatLng(ctx context.Context, lat, lng float64) (_ map[string]string, err error) {
+	ctx, span := startGeocodingSpan(ctx, "address")
+	defer func() {
+		metrics.ObserveGeocoderRequest("address", err)
+		endGeocodingSpan(span, err)
+	}()
+
+	url := fmt.Sprintf("https://maps.googleapis.com/maps/api/geocode/json?latlng=%f,%f&key=%s&language=ja", lat, lng, s.APIKey)
+
+	resp, err := get(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("住所の取得に失敗しました")
+		return nil, fmt.Errorf("住所の取得に失敗しました: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("住所の取得に失敗しました")
+		return nil, fmt.Errorf("住所の取得に失敗しました: status %d", resp.StatusCode)
 	}
 
 	var result struct {
@@ -33,7 +75,11 @@ func (s *GeocodingService) GetAddressFromLatLng(lat, lng float64) (map[string]st
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("住所データの解析に失敗しました")
+		return nil, fmt.Errorf("住所データの解析に失敗しました: %w", err)
+	}
+
+	if len(result.Results) == 0 {
+		return nil, ErrNoAddress
 	}
 
 	var (
@@ -73,6 +119,91 @@ func (s *GeocodingService) GetAddressFromLatLng(lat, lng float64) (map[string]st
 	}, nil
 }
 
+func (s *GeocodingService) GetFormattedAddress(ctx context.Context, lat, lng float64) (_ map[string]string, err error) {
+	ctx, span := startGeocodingSpan(ctx, "formatted_address")
+	defer func() {
+		metrics.ObserveGeocoderRequest("formatted_address", err)
+		endGeocodingSpan(span, err)
+	}()
+
+	url := fmt.Sprintf(
+		"https://maps.googleapis.com/maps/api/geocode/json?latlng=%f,%f&key=%s&language=ja",
+		lat, lng, s.APIKey,
+	)
+
+	resp, err := get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Results []struct {
+			FormattedAddress  string `json:"formatted_address"`
+			AddressComponents []struct {
+				Types    []string `json:"types"`
+				LongName string   `json:"long_name"`
+			} `json:"address_components"`
+		} `json:"results"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Results) == 0 {
+		return nil, ErrNoAddress
+	}
+
+	// 郵便番号を探す
+	var postalCode string
+	for _, component := range result.Results[0].AddressComponents {
+		for _, type_ := range component.Types {
+			if type_ == "postal_code" {
+				postalCode = component.LongName
+				break
+			}
+		}
+	}
+
+	return map[string]string{
+		"address":    result.Results[0].FormattedAddress,
+		"postalCode": postalCode,
+	}, nil
+}
+
+// startGeocodingSpan はGeocoding APIの呼び出しのスパンを開始する（APIキーを含むURLは記録しない）
+func startGeocodingSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "geocoder."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodGet,
+			semconv.ServerAddress(geocodingHost),
+		),
+	)
+}
+
+func endGeocodingSpan(span trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// get はctxのキャンセルとスパンを引き継いでGETリクエストを送り、ステータスコードをスパンに記録する
+func get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	trace.SpanFromContext(ctx).SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	return resp, nil
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
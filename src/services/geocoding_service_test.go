@@ -0,0 +1,102 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"seicheese/services"
+)
+
+// roundTripFunc はGeocoding APIの代わりに固定のレスポンスを返すhttp.RoundTripper
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// fakeGeocodingAPI はhttp.DefaultClientの送信先をテストの間だけ置き換える
+func fakeGeocodingAPI(t *testing.T, status int, body string) {
+	t.Helper()
+	original := http.DefaultClient.Transport
+	http.DefaultClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+	t.Cleanup(func() { http.DefaultClient.Transport = original })
+}
+
+func TestGeocodingService(t *testing.T) {
+	service := &services.GeocodingService{APIKey: "test-key"}
+	methods := map[string]func(ctx context.Context, lat, lng float64) (map[string]string, error){
+		"GetAddressFromLatLng": service.GetAddressFromLatLng,
+		"GetFormattedAddress":  service.GetFormattedAddress,
+	}
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr func(err error) bool
+	}{
+		// 海上など住所のない位置では、Geocoding APIは200でZERO_RESULTSを返す
+		{"zero results", http.StatusOK, `{"results":[],"status":"ZERO_RESULTS"}`, func(err error) bool {
+			return errors.Is(err, services.ErrNoAddress)
+		}},
+		// 解析のエラーは原因を含めて返す
+		{"truncated json", http.StatusOK, `{"results":`, func(err error) bool {
+			return errors.Is(err, io.ErrUnexpectedEOF)
+		}},
+	}
+	for _, tt := range tests {
+		for name, method := range methods {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				fakeGeocodingAPI(t, tt.status, tt.body)
+				data, err := method(context.Background(), 30.0, 135.0)
+				if err == nil || !tt.wantErr(err) {
+					t.Errorf("%s = %v, %v; unexpected error", name, data, err)
+				}
+			})
+		}
+	}
+
+	// 住所の構成要素から住所と郵便番号を組み立てる
+	fakeGeocodingAPI(t, http.StatusOK, `{"status":"OK","results":[{"formatted_address":"日本、〒160-0018 東京都新宿区須賀町５","address_components":[
+		{"long_name":"5","types":["premise"]},
+		{"long_name":"須賀町","types":["political","sublocality","sublocality_level_2"]},
+		{"long_name":"新宿区","types":["locality","political"]},
+		{"long_name":"東京都","types":["administrative_area_level_1","political"]},
+		{"long_name":"160-0018","types":["postal_code"]}
+	]}]}`)
+	data, err := service.GetAddressFromLatLng(context.Background(), 35.6851, 139.7224)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data["address"] != "東京都新宿区須賀町5" || data["postalCode"] != "160-0018" {
+		t.Errorf("GetAddressFromLatLng = %v, want 東京都新宿区須賀町5 160-0018", data)
+	}
+}
+
+func TestGeocodingServiceWrapsErrors(t *testing.T) {
+	service := &services.GeocodingService{APIKey: "test-key"}
+
+	// 送信に失敗した場合は原因のエラーをそのまま含める
+	cause := errors.New("connection refused")
+	original := http.DefaultClient.Transport
+	http.DefaultClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) { return nil, cause })
+	t.Cleanup(func() { http.DefaultClient.Transport = original })
+
+	if _, err := service.GetAddressFromLatLng(context.Background(), 35.6851, 139.7224); !errors.Is(err, cause) {
+		t.Errorf("error = %v, want wrapping %v", err, cause)
+	}
+
+	fakeGeocodingAPI(t, http.StatusForbidden, `{}`)
+	if _, err := service.GetAddressFromLatLng(context.Background(), 35.6851, 139.7224); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("error = %v, want status 403", err)
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"seicheese/internal/apperror"
+	"seicheese/internal/metrics"
+	"seicheese/internal/repository"
+	"seicheese/internal/utils"
+	"seicheese/models"
+
+	"github.com/volatiletech/null/v8"
+)
+
+const (
+	MaxDisplayNameLength = 50
+	MaxBioLength         = 500
+)
+
+// ErrFirebaseIDRequired はFirebase UIDが空の場合のエラー
+var ErrFirebaseIDRequired = errors.New("firebase id is required")
+
+// ProvisionParams はユーザー作成時の入力
+type ProvisionParams struct {
+	FirebaseID  string
+	DisplayName string
+}
+
+// UserService はユーザーの作成を一元化するサービス
+// サインアップ・ユーザー登録など、ユーザーを作成する経路はすべてこれを使う
+type UserService struct {
+	Users repository.UserRepository
+	// 表示名・自己紹介の検証に使うNGワード（nilの場合はデフォルトのNGワードのみ）
+	NGWords *utils.NGWords
+}
+
+// Provision はFirebase UIDに対応するユーザーを取得し、存在しなければ作成する
+// firebase_idのUNIQUE制約を使ったupsertで行うため、同時に呼ばれても作成されるのは1件のみ
+// 戻り値のboolは今回の呼び出しで新規作成されたかどうか
+func (s *UserService) Provision(ctx context.Context, params ProvisionParams) (*models.User, bool, error) {
+	if params.FirebaseID == "" {
+		return nil, false, ErrFirebaseIDRequired
+	}
+
+	displayName := strings.TrimSpace(params.DisplayName)
+	if displayName != "" {
+		if err := s.ValidateDisplayName(displayName); err != nil {
+			return nil, false, err
+		}
+	}
+
+	user := &models.User{
+		FirebaseID:  params.FirebaseID,
+		DisplayName: null.NewString(displayName, displayName != ""),
+	}
+
+	created, err := s.Users.InsertIfNotExists(ctx, user)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to provision user: %w", err)
+	}
+	if created {
+		metrics.SignUps.Inc()
+	}
+
+	return user, created, nil
+}
+
+// ValidateDisplayName 表示名の検証
+func (s *UserService) ValidateDisplayName(name string) error {
+	length := utf8.RuneCountInString(name)
+	if length == 0 {
+		return apperror.ErrDisplayNameRequired
+	}
+	if length > MaxDisplayNameLength {
+		return apperror.ErrDisplayNameTooLong.WithDetails(map[string]interface{}{"max": MaxDisplayNameLength})
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return apperror.ErrDisplayNameInvalidChars
+		}
+	}
+	if s.NGWords.Contains(name) {
+		return apperror.ErrDisplayNameInappropriate
+	}
+	return nil
+}
+
+// ValidateBio 自己紹介文の検証
+func (s *UserService) ValidateBio(bio string) error {
+	if utf8.RuneCountInString(bio) > MaxBioLength {
+		return apperror.ErrBioTooLong.WithDetails(map[string]interface{}{"max": MaxBioLength})
+	}
+	if s.NGWords.Contains(bio) {
+		return apperror.ErrBioInappropriate
+	}
+	return nil
+}
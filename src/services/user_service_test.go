@@ -0,0 +1,107 @@
+package services_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"seicheese/internal/infrastructure/database/dbtest"
+	"seicheese/internal/repository"
+	"seicheese/models"
+	"seicheese/services"
+)
+
+// MySQLを使うテストはTEST_DB_DSNを設定するか、mysqldをインストールした環境でのみ実行される
+//
+//	TEST_DB_DSN='root:pass@tcp(127.0.0.1:3312)/' go test ./services
+func TestMain(m *testing.M) { dbtest.Main(m) }
+
+// 同時に呼び出す数
+const provisionConcurrency = 20
+
+func TestProvisionConcurrent(t *testing.T) {
+	tests := []struct {
+		name string
+		// ユーザーのリポジトリと、firebase_idが一致するユーザーの数を返す関数
+		setup func(t *testing.T) (repository.UserRepository, func(firebaseID string) int)
+	}{
+		{"memory", func(t *testing.T) (repository.UserRepository, func(string) int) {
+			store := repository.NewMemoryStore()
+			count := func(firebaseID string) int {
+				var n int
+				for _, user := range store.Users() {
+					if user.FirebaseID == firebaseID {
+						n++
+					}
+				}
+				return n
+			}
+			return repository.NewMemoryRepositories(store).Users, count
+		}},
+		{"mysql", func(t *testing.T) (repository.UserRepository, func(string) int) {
+			db := dbtest.NewMigrated(t)
+			count := func(firebaseID string) int {
+				n, err := models.Users(models.UserWhere.FirebaseID.EQ(firebaseID)).Count(context.Background(), db)
+				if err != nil {
+					t.Fatalf("count users: %v", err)
+				}
+				return int(n)
+			}
+			return repository.NewSQLRepositories(db).Users, count
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			users, countUsers := tt.setup(t)
+			service := &services.UserService{Users: users}
+
+			type result struct {
+				user    *models.User
+				created bool
+				err     error
+			}
+			results := make([]result, provisionConcurrency)
+			start := make(chan struct{})
+			var wg sync.WaitGroup
+			for i := range results {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					<-start
+					user, created, err := service.Provision(ctx, services.ProvisionParams{FirebaseID: "uid-concurrent"})
+					results[i] = result{user, created, err}
+				}(i)
+			}
+			// できるだけ同時に呼び出す
+			close(start)
+			wg.Wait()
+
+			var created int
+			for i, r := range results {
+				if r.err != nil {
+					t.Fatalf("Provision #%d: %v", i, r.err)
+				}
+				if r.created {
+					created++
+				}
+			}
+			if created != 1 {
+				t.Errorf("created %d times, want exactly 1", created)
+			}
+
+			stored, err := users.FindByFirebaseID(ctx, "uid-concurrent")
+			if err != nil {
+				t.Fatalf("FindByFirebaseID: %v", err)
+			}
+			for i, r := range results {
+				if r.user.UserID != stored.UserID {
+					t.Errorf("Provision #%d returned user %d, want %d", i, r.user.UserID, stored.UserID)
+				}
+			}
+			if count := countUsers("uid-concurrent"); count != 1 {
+				t.Errorf("%d users with firebase_id uid-concurrent, want 1", count)
+			}
+		})
+	}
+}